@@ -0,0 +1,364 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jldevictoria/lithdat/pkg/lithdat"
+)
+
+// FieldChange records a single named value that differs between two
+// worlds.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ObjectDiff is the set of changes to one WORLDOBJECT matched between
+// the two worlds by identity (see objectIdentities); Key is its Name
+// property, or its identity if it has none.
+type ObjectDiff struct {
+	Key               string        `json:"key"`
+	PropertiesAdded   []string      `json:"properties_added,omitempty"`
+	PropertiesRemoved []string      `json:"properties_removed,omitempty"`
+	PropertiesChanged []FieldChange `json:"properties_changed,omitempty"`
+}
+
+func (o ObjectDiff) empty() bool {
+	return len(o.PropertiesAdded) == 0 && len(o.PropertiesRemoved) == 0 && len(o.PropertiesChanged) == 0
+}
+
+// WorldModelDiff is the set of count/bbox changes to one WORLDMODEL
+// matched between the two worlds by key (see worldModelKey).
+type WorldModelDiff struct {
+	Key            string `json:"key"`
+	BBoxChanged    bool   `json:"bbox_changed"`
+	VertexCountOld int    `json:"vertex_count_old"`
+	VertexCountNew int    `json:"vertex_count_new"`
+	PlaneCountOld  int    `json:"plane_count_old"`
+	PlaneCountNew  int    `json:"plane_count_new"`
+}
+
+func (m WorldModelDiff) empty() bool {
+	return !m.BBoxChanged && m.VertexCountOld == m.VertexCountNew && m.PlaneCountOld == m.PlaneCountNew
+}
+
+// Diff is the structural difference between two parsed worlds, at the
+// granularities lithdiff reports: header offsets, world objects and
+// their properties, world model geometry counts, physics polygon
+// counts, and render node counts.
+type Diff struct {
+	HeaderChanges []FieldChange `json:"header_changes,omitempty"`
+
+	ObjectsAdded   []string     `json:"objects_added,omitempty"`
+	ObjectsRemoved []string     `json:"objects_removed,omitempty"`
+	ObjectsChanged []ObjectDiff `json:"objects_changed,omitempty"`
+
+	WorldModelsAdded   []string         `json:"world_models_added,omitempty"`
+	WorldModelsRemoved []string         `json:"world_models_removed,omitempty"`
+	WorldModelsChanged []WorldModelDiff `json:"world_models_changed,omitempty"`
+
+	PhysicsPolyCountOld int `json:"physics_poly_count_old"`
+	PhysicsPolyCountNew int `json:"physics_poly_count_new"`
+
+	RenderNodeCountOld int `json:"render_node_count_old"`
+	RenderNodeCountNew int `json:"render_node_count_new"`
+}
+
+// Empty reports whether old and new describe no differences at all.
+func (d *Diff) Empty() bool {
+	return len(d.HeaderChanges) == 0 &&
+		len(d.ObjectsAdded) == 0 && len(d.ObjectsRemoved) == 0 && len(d.ObjectsChanged) == 0 &&
+		len(d.WorldModelsAdded) == 0 && len(d.WorldModelsRemoved) == 0 && len(d.WorldModelsChanged) == 0 &&
+		d.PhysicsPolyCountOld == d.PhysicsPolyCountNew &&
+		d.RenderNodeCountOld == d.RenderNodeCountNew
+}
+
+// Compute builds the structural diff between old and new.
+func Compute(old, new *lithdat.WORLD) *Diff {
+	d := &Diff{
+		PhysicsPolyCountOld: len(old.Polies),
+		PhysicsPolyCountNew: len(new.Polies),
+		RenderNodeCountOld:  len(old.RenderData.RenderNodes),
+		RenderNodeCountNew:  len(new.RenderData.RenderNodes),
+	}
+	d.HeaderChanges = diffHeader(old.Header, new.Header)
+	d.ObjectsAdded, d.ObjectsRemoved, d.ObjectsChanged = diffObjects(old.WorldObjects, new.WorldObjects)
+	d.WorldModelsAdded, d.WorldModelsRemoved, d.WorldModelsChanged = diffWorldModels(old.WorldTree.WorldModels, new.WorldTree.WorldModels)
+	return d
+}
+
+func diffHeader(old, new lithdat.HEADER) []FieldChange {
+	fields := []struct {
+		name     string
+		old, new uint32
+	}{
+		{"Version", old.Version, new.Version},
+		{"ObjectDataPos", old.ObjectDataPos, new.ObjectDataPos},
+		{"BlindObjectDataPos", old.BlindObjectDataPos, new.BlindObjectDataPos},
+		{"LightgridPos", old.LightgridPos, new.LightgridPos},
+		{"CollisionDataPos", old.CollisionDataPos, new.CollisionDataPos},
+		{"ParticleBlockerDataPos", old.ParticleBlockerDataPos, new.ParticleBlockerDataPos},
+		{"RenderDataPos", old.RenderDataPos, new.RenderDataPos},
+		{"PackerType", old.PackerType, new.PackerType},
+		{"PackerVersion", old.PackerVersion, new.PackerVersion},
+	}
+
+	var changes []FieldChange
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, FieldChange{
+				Field: f.name,
+				Old:   fmt.Sprintf("%d", f.old),
+				New:   fmt.Sprintf("%d", f.new),
+			})
+		}
+	}
+	return changes
+}
+
+// objectIdentity is a WORLDOBJECT's stable identity across two worlds:
+// its ObjectType plus its occurrence index among objects of that same
+// type. This is what old and new objects are matched on, so that a
+// Name property changing surfaces as a rename (a property delta on an
+// otherwise-matched object) instead of spurious add/remove noise.
+func objectIdentities(objects []lithdat.WORLDOBJECT) []string {
+	seen := make(map[string]int, len(objects))
+	identities := make([]string, len(objects))
+	for i, obj := range objects {
+		n := seen[obj.ObjectType]
+		seen[obj.ObjectType] = n + 1
+		identities[i] = fmt.Sprintf("%s#%d", obj.ObjectType, n)
+	}
+	return identities
+}
+
+// objectName returns obj's Name property, for display, or "" if it has
+// none.
+func objectName(obj lithdat.WORLDOBJECT) string {
+	for _, p := range obj.Properties {
+		if p.Name == "Name" && p.DataTypeFlag == 0 {
+			return p.DataString
+		}
+	}
+	return ""
+}
+
+// objectDisplayKey is the label used in diff output: the object's Name
+// property when it has one, otherwise its identity.
+func objectDisplayKey(obj lithdat.WORLDOBJECT, identity string) string {
+	if name := objectName(obj); name != "" {
+		return name
+	}
+	return identity
+}
+
+func diffObjects(old, new []lithdat.WORLDOBJECT) (added, removed []string, changed []ObjectDiff) {
+	oldIdentities := objectIdentities(old)
+	oldByIdentity := make(map[string]lithdat.WORLDOBJECT, len(old))
+	for i, obj := range old {
+		oldByIdentity[oldIdentities[i]] = obj
+	}
+
+	newIdentities := objectIdentities(new)
+	newByIdentity := make(map[string]lithdat.WORLDOBJECT, len(new))
+	for i, obj := range new {
+		newByIdentity[newIdentities[i]] = obj
+	}
+
+	for i, obj := range old {
+		identity := oldIdentities[i]
+		newObj, ok := newByIdentity[identity]
+		if !ok {
+			removed = append(removed, objectDisplayKey(obj, identity))
+			continue
+		}
+		if od := diffObject(objectDisplayKey(newObj, identity), obj, newObj); !od.empty() {
+			changed = append(changed, od)
+		}
+	}
+	for i, obj := range new {
+		identity := newIdentities[i]
+		if _, ok := oldByIdentity[identity]; !ok {
+			added = append(added, objectDisplayKey(obj, identity))
+		}
+	}
+	return added, removed, changed
+}
+
+func diffObject(key string, old, new lithdat.WORLDOBJECT) ObjectDiff {
+	od := ObjectDiff{Key: key}
+
+	newProps := make(map[string]lithdat.PROPERTY, len(new.Properties))
+	for _, p := range new.Properties {
+		newProps[p.Name] = p
+	}
+	oldProps := make(map[string]lithdat.PROPERTY, len(old.Properties))
+	for _, p := range old.Properties {
+		oldProps[p.Name] = p
+	}
+
+	for _, p := range old.Properties {
+		np, ok := newProps[p.Name]
+		if !ok {
+			od.PropertiesRemoved = append(od.PropertiesRemoved, p.Name)
+			continue
+		}
+		oldVal, newVal := propertyValue(p), propertyValue(np)
+		if oldVal != newVal {
+			od.PropertiesChanged = append(od.PropertiesChanged, FieldChange{Field: p.Name, Old: oldVal, New: newVal})
+		}
+	}
+	for _, p := range new.Properties {
+		if _, ok := oldProps[p.Name]; !ok {
+			od.PropertiesAdded = append(od.PropertiesAdded, p.Name)
+		}
+	}
+	return od
+}
+
+// propertyValue renders a PROPERTY's active DataTypeFlag union member
+// as text, for display and comparison.
+func propertyValue(p lithdat.PROPERTY) string {
+	switch p.DataTypeFlag {
+	case 0:
+		return p.DataString
+	case 1:
+		return fmt.Sprintf("(%g, %g, %g)", p.DataVEC3.X, p.DataVEC3.Y, p.DataVEC3.Z)
+	case 2:
+		return fmt.Sprintf("(%g, %g, %g)", p.DataCOLOR.R, p.DataCOLOR.G, p.DataCOLOR.B)
+	case 3:
+		return fmt.Sprintf("%g", p.DataFloat)
+	case 5:
+		return fmt.Sprintf("%v", p.DataBool)
+	case 6:
+		return fmt.Sprintf("%d", p.DataUint32)
+	case 7:
+		return fmt.Sprintf("(%g, %g, %g, %g)", p.DataQuaternion.X, p.DataQuaternion.Y, p.DataQuaternion.Z, p.DataQuaternion.W)
+	default:
+		return fmt.Sprintf("<unknown type %d>", p.DataTypeFlag)
+	}
+}
+
+// worldModelKey identifies a WORLDMODEL across two worlds: by its
+// WorldName, falling back to its position for the rare unnamed model.
+func worldModelKey(wm lithdat.WORLDMODEL, idx int) string {
+	if wm.WorldName != "" {
+		return wm.WorldName
+	}
+	return fmt.Sprintf("model#%d", idx)
+}
+
+func diffWorldModels(old, new []lithdat.WORLDMODEL) (added, removed []string, changed []WorldModelDiff) {
+	oldByKey := make(map[string]lithdat.WORLDMODEL, len(old))
+	var oldKeys []string
+	for i, wm := range old {
+		key := worldModelKey(wm, i)
+		oldByKey[key] = wm
+		oldKeys = append(oldKeys, key)
+	}
+
+	newByKey := make(map[string]lithdat.WORLDMODEL, len(new))
+	var newKeys []string
+	for i, wm := range new {
+		key := worldModelKey(wm, i)
+		newByKey[key] = wm
+		newKeys = append(newKeys, key)
+	}
+
+	for _, key := range oldKeys {
+		newWm, ok := newByKey[key]
+		if !ok {
+			removed = append(removed, key)
+			continue
+		}
+		oldWm := oldByKey[key]
+		wd := WorldModelDiff{
+			Key:            key,
+			BBoxChanged:    oldWm.WorldBBoxMin != newWm.WorldBBoxMin || oldWm.WorldBBoxMax != newWm.WorldBBoxMax,
+			VertexCountOld: len(oldWm.Points),
+			VertexCountNew: len(newWm.Points),
+			PlaneCountOld:  len(oldWm.Planes),
+			PlaneCountNew:  len(newWm.Planes),
+		}
+		if !wd.empty() {
+			changed = append(changed, wd)
+		}
+	}
+	for _, key := range newKeys {
+		if _, ok := oldByKey[key]; !ok {
+			added = append(added, key)
+		}
+	}
+	return added, removed, changed
+}
+
+// String renders the diff as stable, human-readable text.
+func (d *Diff) String() string {
+	var b strings.Builder
+	if d.Empty() {
+		b.WriteString("no differences\n")
+		return b.String()
+	}
+
+	if len(d.HeaderChanges) > 0 {
+		fmt.Fprintln(&b, "Header:")
+		for _, c := range d.HeaderChanges {
+			fmt.Fprintf(&b, "  %s: %s -> %s\n", c.Field, c.Old, c.New)
+		}
+	}
+
+	if len(d.ObjectsAdded) > 0 || len(d.ObjectsRemoved) > 0 || len(d.ObjectsChanged) > 0 {
+		fmt.Fprintln(&b, "World objects:")
+		for _, key := range d.ObjectsAdded {
+			fmt.Fprintf(&b, "  + %s\n", key)
+		}
+		for _, key := range d.ObjectsRemoved {
+			fmt.Fprintf(&b, "  - %s\n", key)
+		}
+		for _, od := range d.ObjectsChanged {
+			fmt.Fprintf(&b, "  ~ %s\n", od.Key)
+			for _, name := range od.PropertiesAdded {
+				fmt.Fprintf(&b, "      + %s\n", name)
+			}
+			for _, name := range od.PropertiesRemoved {
+				fmt.Fprintf(&b, "      - %s\n", name)
+			}
+			for _, c := range od.PropertiesChanged {
+				fmt.Fprintf(&b, "      %s: %s -> %s\n", c.Field, c.Old, c.New)
+			}
+		}
+	}
+
+	if len(d.WorldModelsAdded) > 0 || len(d.WorldModelsRemoved) > 0 || len(d.WorldModelsChanged) > 0 {
+		fmt.Fprintln(&b, "World models:")
+		for _, key := range d.WorldModelsAdded {
+			fmt.Fprintf(&b, "  + %s\n", key)
+		}
+		for _, key := range d.WorldModelsRemoved {
+			fmt.Fprintf(&b, "  - %s\n", key)
+		}
+		for _, wd := range d.WorldModelsChanged {
+			fmt.Fprintf(&b, "  ~ %s\n", wd.Key)
+			if wd.BBoxChanged {
+				fmt.Fprintln(&b, "      bbox changed")
+			}
+			if wd.VertexCountOld != wd.VertexCountNew {
+				fmt.Fprintf(&b, "      vertices: %d -> %d\n", wd.VertexCountOld, wd.VertexCountNew)
+			}
+			if wd.PlaneCountOld != wd.PlaneCountNew {
+				fmt.Fprintf(&b, "      planes: %d -> %d\n", wd.PlaneCountOld, wd.PlaneCountNew)
+			}
+		}
+	}
+
+	if d.PhysicsPolyCountOld != d.PhysicsPolyCountNew {
+		fmt.Fprintf(&b, "Physics polygons: %d -> %d\n", d.PhysicsPolyCountOld, d.PhysicsPolyCountNew)
+	}
+	if d.RenderNodeCountOld != d.RenderNodeCountNew {
+		fmt.Fprintf(&b, "Render nodes: %d -> %d\n", d.RenderNodeCountOld, d.RenderNodeCountNew)
+	}
+
+	return b.String()
+}
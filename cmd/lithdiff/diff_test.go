@@ -0,0 +1,115 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jldevictoria/lithdat/pkg/lithdat"
+)
+
+func baseWorld() *lithdat.WORLD {
+	return &lithdat.WORLD{
+		Header: lithdat.HEADER{Version: 1, ObjectDataPos: 100, RenderDataPos: 200},
+		WorldTree: lithdat.WORLDTREE{
+			WorldModels: []lithdat.WORLDMODEL{
+				{
+					WorldName: "physics",
+					Planes:    []lithdat.PLANE{{}, {}},
+					Points:    []lithdat.VEC3{{}, {}, {}},
+				},
+			},
+		},
+		WorldObjects: []lithdat.WORLDOBJECT{
+			{
+				ObjectType: "Light",
+				Properties: []lithdat.PROPERTY{
+					{Name: "Name", DataTypeFlag: 0, DataString: "torch1"},
+					{Name: "Pos", DataTypeFlag: 1, DataVEC3: lithdat.VEC3{X: 1, Y: 2, Z: 3}},
+				},
+			},
+		},
+		Polies: []lithdat.POLYGON{{}, {}},
+		RenderData: lithdat.RENDERDATA{
+			RenderNodes: []lithdat.RENDERNODE{{}},
+		},
+	}
+}
+
+func TestComputeNoDifferences(t *testing.T) {
+	w := baseWorld()
+	d := Compute(w, w)
+	if !d.Empty() {
+		t.Fatalf("Compute(w, w) not empty: %+v", d)
+	}
+	if got := d.String(); got != "no differences\n" {
+		t.Errorf("String() = %q, want %q", got, "no differences\n")
+	}
+}
+
+func TestComputeDetectsChanges(t *testing.T) {
+	old := baseWorld()
+	new := baseWorld()
+
+	new.Header.RenderDataPos = 250
+
+	new.WorldObjects[0].Properties[1].DataVEC3 = lithdat.VEC3{X: 9, Y: 9, Z: 9}
+	new.WorldObjects = append(new.WorldObjects, lithdat.WORLDOBJECT{
+		ObjectType: "SpawnPoint",
+		Properties: []lithdat.PROPERTY{{Name: "Name", DataTypeFlag: 0, DataString: "spawn1"}},
+	})
+
+	new.WorldTree.WorldModels[0].Points = append(new.WorldTree.WorldModels[0].Points, lithdat.VEC3{X: 1})
+
+	new.Polies = append(new.Polies, lithdat.POLYGON{})
+
+	d := Compute(old, new)
+	if d.Empty() {
+		t.Fatal("Compute reported no differences")
+	}
+
+	if len(d.HeaderChanges) != 1 || d.HeaderChanges[0].Field != "RenderDataPos" {
+		t.Errorf("HeaderChanges = %+v, want a single RenderDataPos change", d.HeaderChanges)
+	}
+
+	if len(d.ObjectsAdded) != 1 || d.ObjectsAdded[0] != "spawn1" {
+		t.Errorf("ObjectsAdded = %v, want [spawn1]", d.ObjectsAdded)
+	}
+	if len(d.ObjectsChanged) != 1 || d.ObjectsChanged[0].Key != "torch1" {
+		t.Fatalf("ObjectsChanged = %+v, want a single torch1 entry", d.ObjectsChanged)
+	}
+	if len(d.ObjectsChanged[0].PropertiesChanged) != 1 || d.ObjectsChanged[0].PropertiesChanged[0].Field != "Pos" {
+		t.Errorf("ObjectsChanged[0].PropertiesChanged = %+v, want a single Pos change", d.ObjectsChanged[0].PropertiesChanged)
+	}
+
+	if len(d.WorldModelsChanged) != 1 || d.WorldModelsChanged[0].VertexCountNew != 4 {
+		t.Errorf("WorldModelsChanged = %+v, want physics vertex count 4", d.WorldModelsChanged)
+	}
+
+	if d.PhysicsPolyCountOld != 2 || d.PhysicsPolyCountNew != 3 {
+		t.Errorf("PhysicsPolyCount = %d -> %d, want 2 -> 3", d.PhysicsPolyCountOld, d.PhysicsPolyCountNew)
+	}
+
+	text := d.String()
+	for _, want := range []string{"RenderDataPos: 200 -> 250", "+ spawn1", "~ torch1", "Pos:", "vertices: 3 -> 4", "Physics polygons: 2 -> 3"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("String() missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestComputeRenamedObjectIsAPropertyChange(t *testing.T) {
+	old := baseWorld()
+	new := baseWorld()
+	new.WorldObjects[0].Properties[0].DataString = "torch2"
+
+	d := Compute(old, new)
+	if len(d.ObjectsAdded) != 0 || len(d.ObjectsRemoved) != 0 {
+		t.Fatalf("ObjectsAdded = %v, ObjectsRemoved = %v, want a rename to match instead of add/remove", d.ObjectsAdded, d.ObjectsRemoved)
+	}
+	if len(d.ObjectsChanged) != 1 || d.ObjectsChanged[0].Key != "torch2" {
+		t.Fatalf("ObjectsChanged = %+v, want a single entry keyed by the new name torch2", d.ObjectsChanged)
+	}
+	if len(d.ObjectsChanged[0].PropertiesChanged) != 1 || d.ObjectsChanged[0].PropertiesChanged[0] != (FieldChange{Field: "Name", Old: "torch1", New: "torch2"}) {
+		t.Errorf("PropertiesChanged = %+v, want a single Name: torch1 -> torch2 change", d.ObjectsChanged[0].PropertiesChanged)
+	}
+}
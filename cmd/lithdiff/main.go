@@ -0,0 +1,70 @@
+// Command lithdiff reports structural differences between two
+// Lithtech world (.dat) files: header offsets, added/removed/renamed
+// world objects and their property values, world model geometry
+// counts, physics polygon counts, and render node counts.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Jldevictoria/lithdat/pkg/lithdat"
+)
+
+func main() {
+	jsonOut := flag.Bool("json", false, "output the diff as JSON instead of text")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [-json] <old.dat> <new.dat>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	differs, err := run(flag.Arg(0), flag.Arg(1), *jsonOut)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if differs {
+		os.Exit(1)
+	}
+}
+
+// run reports whether old and new differ, after printing the diff to
+// stdout.
+func run(oldPath, newPath string, asJSON bool) (bool, error) {
+	old, err := loadWorld(oldPath)
+	if err != nil {
+		return false, fmt.Errorf("loading %s: %w", oldPath, err)
+	}
+	new, err := loadWorld(newPath)
+	if err != nil {
+		return false, fmt.Errorf("loading %s: %w", newPath, err)
+	}
+
+	d := Compute(old, new)
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(d); err != nil {
+			return false, fmt.Errorf("encoding diff: %w", err)
+		}
+	} else {
+		fmt.Print(d)
+	}
+	return !d.Empty(), nil
+}
+
+func loadWorld(path string) (*lithdat.WORLD, error) {
+	f, err := lithdat.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.World()
+}
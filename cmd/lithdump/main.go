@@ -0,0 +1,74 @@
+// Command lithdump prints the structure of a Lithtech world (.dat) file.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Jldevictoria/lithdat/pkg/lithdat"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintf(os.Stderr, "usage: %s <world.dat>\n", os.Args[0])
+		os.Exit(1)
+	}
+	if err := run(os.Args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(filename string) error {
+	f, err := lithdat.Open(filename)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", filename, err)
+	}
+	defer f.Close()
+
+	fmt.Println(f.Header)
+
+	objects, err := f.WorldObjects()
+	if err != nil {
+		return fmt.Errorf("reading world objects: %w", err)
+	}
+	fmt.Println("\nWorld Objects:", len(objects))
+	for _, obj := range objects {
+		fmt.Println("\nProperty Count (", obj.ObjectType, "):", obj.PropertiesLen)
+		for _, prop := range obj.Properties {
+			fmt.Println(prop)
+		}
+	}
+
+	tree, err := f.WorldTree()
+	if err != nil {
+		return fmt.Errorf("reading world tree: %w", err)
+	}
+	fmt.Println("\nWorld Models:", tree.WorldModelsLen)
+
+	lightGrid, err := f.LightGrid()
+	if err != nil {
+		return fmt.Errorf("reading lightgrid: %w", err)
+	}
+	fmt.Println("\nLightgrid bytes:", lightGrid.LgDataLen)
+
+	polies, err := f.CollisionPolies()
+	if err != nil {
+		return fmt.Errorf("reading collision polies: %w", err)
+	}
+	fmt.Println("\nCollision Polygons:", len(polies))
+
+	blockers, err := f.ParticleBlockers()
+	if err != nil {
+		return fmt.Errorf("reading particle blockers: %w", err)
+	}
+	fmt.Println("Particle Blockers:", len(blockers))
+
+	renderData, err := f.RenderData()
+	if err != nil {
+		return fmt.Errorf("reading render data: %w", err)
+	}
+	fmt.Println("\nRender Tree Nodes:", renderData.RenderTreeNodesLen)
+
+	return nil
+}
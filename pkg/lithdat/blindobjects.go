@@ -0,0 +1,64 @@
+package lithdat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// BlindObjectsData returns the world's blind objects: opaque, engine-
+// specific blobs keyed by a DataID that the world format otherwise
+// doesn't interpret. It is decoded from BlindObjectDataPos on first
+// call.
+func (f *File) BlindObjectsData() ([]BLINDOBJECTDATA, error) {
+	f.blindObjectsOnce.Do(func() {
+		f.blindObjects, f.blindObjectsErr = f.readBlindObjectsData()
+	})
+	return f.blindObjects, f.blindObjectsErr
+}
+
+func (f *File) readBlindObjectsData() ([]BLINDOBJECTDATA, error) {
+	r := f.sectionReader(f.Header.BlindObjectDataPos)
+
+	if err := binary.Read(r, binary.LittleEndian, &f.blindObjectsSize); err != nil {
+		return nil, fmt.Errorf("lithdat: reading blind object count: %w", err)
+	}
+
+	objects := make([]BLINDOBJECTDATA, f.blindObjectsSize)
+	for i := range objects {
+		o, err := readBlindObjectData(r)
+		if err != nil {
+			return nil, fmt.Errorf("lithdat: reading blind object %d: %w", i, err)
+		}
+		objects[i] = o
+	}
+	return objects, nil
+}
+
+func readBlindObjectData(r io.Reader) (BLINDOBJECTDATA, error) {
+	var o BLINDOBJECTDATA
+	if err := binary.Read(r, binary.LittleEndian, &o.Len); err != nil {
+		return o, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &o.DataID); err != nil {
+		return o, err
+	}
+	o.Data = make([]byte, o.Len)
+	if _, err := io.ReadFull(r, o.Data); err != nil {
+		return o, fmt.Errorf("reading blind object data: %w", err)
+	}
+	return o, nil
+}
+
+func writeBlindObjectData(w io.Writer, o BLINDOBJECTDATA) error {
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(o.Data))); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, o.DataID); err != nil {
+		return err
+	}
+	if _, err := w.Write(o.Data); err != nil {
+		return fmt.Errorf("writing blind object data: %w", err)
+	}
+	return nil
+}
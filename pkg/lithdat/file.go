@@ -0,0 +1,174 @@
+// Package lithdat reads (and writes) Lithtech engine WORLD (.dat) files.
+//
+// The API is modeled on debug/pe: open a file with Open or NewFile, then
+// pull out the pieces you need with the section accessors. Each accessor
+// decodes its section from the underlying reader on first use and caches
+// the result, so unrelated sections can be fetched concurrently without
+// paying for sections nobody asked for.
+package lithdat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+)
+
+// File is an open Lithtech world file.
+type File struct {
+	Header HEADER
+
+	r      io.ReaderAt
+	closer io.Closer
+
+	worldTreeOnce sync.Once
+	worldTree     WORLDTREE
+	worldTreeErr  error
+	// Populated alongside worldTree: the WorldInfoStr/extents/offset
+	// preamble that precedes it in the file.
+	worldInfoStr    string
+	worldExtentsMin VEC3
+	worldExtentsMax VEC3
+	worldOffset     VEC3
+
+	blindObjectsOnce sync.Once
+	blindObjects     []BLINDOBJECTDATA
+	blindObjectsSize uint32
+	blindObjectsErr  error
+
+	worldObjectsOnce sync.Once
+	worldObjects     []WORLDOBJECT
+	worldObjectsErr  error
+
+	lightGridOnce sync.Once
+	lightGrid     LIGHTGRID
+	lightGridErr  error
+
+	collisionPoliesOnce sync.Once
+	collisionPolies     []POLYGON
+	collisionPoliesErr  error
+
+	particleBlockersOnce sync.Once
+	particleBlockers     []POLYGON
+	particleBlockersErr  error
+
+	renderDataOnce sync.Once
+	renderData     RENDERDATA
+	renderDataErr  error
+}
+
+// Open opens the named file using os.Open and prepares it for access as
+// a Lithtech WORLD file. The caller must call Close when done.
+func Open(name string) (*File, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	lf, err := NewFile(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	lf.closer = f
+	return lf, nil
+}
+
+// NewFile creates a File for accessing a Lithtech world laid out in r,
+// reading only the fixed-size header up front. Section contents are
+// decoded lazily by the accessor methods.
+func NewFile(r io.ReaderAt) (*File, error) {
+	f := &File{r: r}
+	sr := io.NewSectionReader(r, 0, headerSize)
+	if err := binary.Read(sr, binary.LittleEndian, &f.Header); err != nil {
+		return nil, fmt.Errorf("lithdat: reading header: %w", err)
+	}
+	return f, nil
+}
+
+// headerSize is the on-disk size of HEADER: nine uint32 fields plus the
+// six-uint32 Future reservation.
+const headerSize = 4*9 + 4*6
+
+// Close closes the File. If the File was created with NewFile directly
+// (not Open), Close has no effect.
+func (f *File) Close() error {
+	if f.closer == nil {
+		return nil
+	}
+	err := f.closer.Close()
+	f.closer = nil
+	return err
+}
+
+// World decodes every section and returns them assembled into a single
+// WORLD value, suitable for round-tripping through WriteTo. Callers who
+// only need a few sections should prefer the individual accessors.
+func (f *File) World() (*WORLD, error) {
+	var w WORLD
+	w.Header = f.Header
+
+	tree, err := f.WorldTree()
+	if err != nil {
+		return nil, fmt.Errorf("lithdat: World: %w", err)
+	}
+	w.WorldInfoStr = f.worldInfoStr
+	w.WorldInfoStrLen = uint32(len(f.worldInfoStr))
+	w.WorldExtentsMin = f.worldExtentsMin
+	w.WorldExtentsMax = f.worldExtentsMax
+	w.WorldOffset = f.worldOffset
+	w.WorldTree = tree
+
+	if w.WorldObjects, err = f.WorldObjects(); err != nil {
+		return nil, fmt.Errorf("lithdat: World: %w", err)
+	}
+	w.WorldObjectsLen = uint32(len(w.WorldObjects))
+
+	if w.BlindObjectsData, err = f.BlindObjectsData(); err != nil {
+		return nil, fmt.Errorf("lithdat: World: %w", err)
+	}
+	w.BlindObjectDataSize = f.blindObjectsSize
+
+	if w.LightGrid, err = f.LightGrid(); err != nil {
+		return nil, fmt.Errorf("lithdat: World: %w", err)
+	}
+
+	if w.Polies, err = f.CollisionPolies(); err != nil {
+		return nil, fmt.Errorf("lithdat: World: %w", err)
+	}
+	w.PhysicsDataLen = uint32(len(w.Polies))
+
+	if w.ParticleBlockers, err = f.ParticleBlockers(); err != nil {
+		return nil, fmt.Errorf("lithdat: World: %w", err)
+	}
+	w.ParticleBlockerDataLen = uint32(len(w.ParticleBlockers))
+
+	if w.RenderData, err = f.RenderData(); err != nil {
+		return nil, fmt.Errorf("lithdat: World: %w", err)
+	}
+
+	return &w, nil
+}
+
+// sectionReader returns a reader over everything at and after offset.
+// The section length isn't known up front, so it's bounded only by the
+// underlying ReaderAt's own EOF.
+func (f *File) sectionReader(offset uint32) io.Reader {
+	return io.NewSectionReader(f.r, int64(offset), math.MaxInt64-int64(offset))
+}
+
+// readLithSTRING reads a Lithtech length-prefixed string: a little
+// endian uint16 byte count followed by that many (non-NUL-terminated)
+// bytes.
+func readLithSTRING(r io.Reader) (string, error) {
+	var size uint16
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return "", fmt.Errorf("lithdat: reading string length: %w", err)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", fmt.Errorf("lithdat: reading string data: %w", err)
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,135 @@
+package gltf
+
+// document is the subset of the glTF 2.0 JSON schema this package
+// emits. Field sets are trimmed to what Export actually produces, not
+// the full spec.
+type document struct {
+	Asset       asset        `json:"asset"`
+	Scene       int          `json:"scene"`
+	Scenes      []scene      `json:"scenes"`
+	Nodes       []node       `json:"nodes,omitempty"`
+	Meshes      []mesh       `json:"meshes,omitempty"`
+	Materials   []material   `json:"materials,omitempty"`
+	Textures    []texture    `json:"textures,omitempty"`
+	Images      []image      `json:"images,omitempty"`
+	Accessors   []accessor   `json:"accessors,omitempty"`
+	BufferViews []bufferView `json:"bufferViews,omitempty"`
+	Buffers     []buffer     `json:"buffers,omitempty"`
+	Extensions  *extensions  `json:"extensions,omitempty"`
+	ExtUsed     []string     `json:"extensionsUsed,omitempty"`
+}
+
+type asset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator"`
+}
+
+type scene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type node struct {
+	Name        string     `json:"name,omitempty"`
+	Translation [3]float32 `json:"translation,omitempty"`
+	Mesh        *int       `json:"mesh,omitempty"`
+	Children    []int      `json:"children,omitempty"`
+	Extensions  *nodeExt   `json:"extensions,omitempty"`
+}
+
+type nodeExt struct {
+	Light *lightRef `json:"KHR_lights_punctual,omitempty"`
+}
+
+type lightRef struct {
+	Light int `json:"light"`
+}
+
+type mesh struct {
+	Name       string      `json:"name,omitempty"`
+	Primitives []primitive `json:"primitives"`
+}
+
+type primitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Material   *int           `json:"material,omitempty"`
+	Mode       int            `json:"mode"`
+}
+
+type material struct {
+	Name                 string                `json:"name,omitempty"`
+	PBRMetallicRoughness *pbrMetallicRoughness `json:"pbrMetallicRoughness,omitempty"`
+	EmissiveTexture      *textureInfo          `json:"emissiveTexture,omitempty"`
+	EmissiveFactor       [3]float32            `json:"emissiveFactor,omitempty"`
+}
+
+type pbrMetallicRoughness struct {
+	BaseColorTexture *textureInfo `json:"baseColorTexture,omitempty"`
+	MetallicFactor   float32      `json:"metallicFactor"`
+	RoughnessFactor  float32      `json:"roughnessFactor"`
+}
+
+type textureInfo struct {
+	Index    int `json:"index"`
+	TexCoord int `json:"texCoord,omitempty"`
+}
+
+type texture struct {
+	Source int `json:"source"`
+}
+
+type image struct {
+	URI string `json:"uri"`
+}
+
+type accessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Normalized    bool      `json:"normalized,omitempty"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+type bufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target,omitempty"`
+}
+
+type buffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+type extensions struct {
+	Lights *lightsPunctual `json:"KHR_lights_punctual,omitempty"`
+}
+
+type lightsPunctual struct {
+	Lights []punctualLight `json:"lights"`
+}
+
+type punctualLight struct {
+	Name  string     `json:"name,omitempty"`
+	Type  string     `json:"type"`
+	Color [3]float32 `json:"color,omitempty"`
+	Range float32    `json:"range,omitempty"`
+}
+
+// glTF component types and accessor type strings used by this package.
+const (
+	componentTypeUnsignedInt = 5125
+	componentTypeFloat       = 5126
+
+	accessorVec2 = "VEC2"
+	accessorVec3 = "VEC3"
+	accessorVec4 = "VEC4"
+	accessorScal = "SCALAR"
+
+	targetArrayBuffer        = 34962
+	targetElementArrayBuffer = 34963
+
+	primitiveModeTriangles = 4
+)
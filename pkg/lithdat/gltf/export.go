@@ -0,0 +1,406 @@
+// Package gltf exports a parsed Lithtech WORLD as a glTF 2.0 binary
+// (.glb) scene: the render tree becomes the node/mesh hierarchy, each
+// SECTION's textures become PBR materials, lightmaps are baked into the
+// second UV set's emissive channel, and WORLDOBJECTs that look like
+// lights become KHR_lights_punctual nodes.
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Jldevictoria/lithdat/pkg/lithdat"
+)
+
+// Export walks w's render tree and world objects and writes the
+// resulting scene to out as a single glTF binary (.glb) blob.
+func Export(w *lithdat.WORLD, out io.Writer) error {
+	b := &builder{
+		doc: document{
+			Asset:  asset{Version: "2.0", Generator: "lithdat"},
+			Scene:  0,
+			Scenes: []scene{{}},
+		},
+		textureIndex:  map[string]int{},
+		materialIndex: map[string]int{},
+	}
+
+	var roots []int
+	if len(w.RenderData.RenderNodes) > 0 {
+		idx, err := b.buildRenderNode(w.RenderData.RenderNodes, 0, map[uint32]bool{})
+		if err != nil {
+			return fmt.Errorf("lithdat/gltf: building render tree: %w", err)
+		}
+		roots = append(roots, idx)
+	}
+
+	lightRoots, err := b.buildLightNodes(w.WorldObjects)
+	if err != nil {
+		return fmt.Errorf("lithdat/gltf: building lights: %w", err)
+	}
+	roots = append(roots, lightRoots...)
+
+	b.doc.Scenes[0].Nodes = roots
+	b.doc.Buffers = []buffer{{ByteLength: b.bin.Len()}}
+
+	return writeGLB(&b.doc, b.bin.Bytes(), out)
+}
+
+// builder accumulates the glTF JSON document and the single binary
+// buffer backing all of its accessors.
+type builder struct {
+	doc document
+	bin bytes.Buffer
+
+	textureIndex  map[string]int
+	materialIndex map[string]int
+}
+
+// buildRenderNode recursively converts a RENDERNODE (and, per
+// ChildFlags, its children) into a glTF node, returning its index.
+func (b *builder) buildRenderNode(nodes []lithdat.RENDERNODE, idx uint32, visiting map[uint32]bool) (int, error) {
+	if int(idx) >= len(nodes) {
+		return 0, fmt.Errorf("render node index %d out of range (%d nodes)", idx, len(nodes))
+	}
+	if visiting[idx] {
+		return 0, fmt.Errorf("render node %d is its own ancestor", idx)
+	}
+	visiting[idx] = true
+	defer delete(visiting, idx)
+
+	rn := nodes[idx]
+	meshIdx, err := b.buildMesh(rn)
+	if err != nil {
+		return 0, fmt.Errorf("render node %d: %w", idx, err)
+	}
+
+	n := node{Translation: vec3Array(rn.VCenter)}
+	if meshIdx >= 0 {
+		n.Mesh = &meshIdx
+	}
+	for child := 0; child < len(rn.ChildNodeIndicies); child++ {
+		if rn.ChildFlags&(1<<uint(child)) == 0 {
+			continue
+		}
+		childIdx, err := b.buildRenderNode(nodes, rn.ChildNodeIndicies[child], visiting)
+		if err != nil {
+			return 0, err
+		}
+		n.Children = append(n.Children, childIdx)
+	}
+
+	b.doc.Nodes = append(b.doc.Nodes, n)
+	return len(b.doc.Nodes) - 1, nil
+}
+
+// buildMesh turns a render node's sections into glTF primitives
+// sharing one set of vertex accessors, consuming triangles from
+// rn.Triangles in order as each section's TriangleCount dictates. It
+// returns -1 (no mesh) if the node carries no geometry.
+func (b *builder) buildMesh(rn lithdat.RENDERNODE) (int, error) {
+	if len(rn.Vertices) == 0 || len(rn.Sections) == 0 {
+		return -1, nil
+	}
+
+	positions := make([]lithdat.VEC3, len(rn.Vertices))
+	normals := make([]lithdat.VEC3, len(rn.Vertices))
+	tangents := make([][4]float32, len(rn.Vertices))
+	uv0 := make([]lithdat.VEC2, len(rn.Vertices))
+	uv1 := make([]lithdat.VEC2, len(rn.Vertices))
+	colors := make([][4]float32, len(rn.Vertices))
+	for i, v := range rn.Vertices {
+		positions[i] = v.VPos
+		normals[i] = v.VNormal
+		tangents[i] = [4]float32{v.VTangent.X, v.VTangent.Y, v.VTangent.Z, 1}
+		uv0[i] = v.Uv0
+		uv1[i] = v.Uv1
+		colors[i] = unpackColor(v.Color)
+	}
+
+	posAcc := b.addVec3PositionAccessor(positions)
+	normAcc := b.addVec3Accessor(normals)
+	tanAcc := b.addVec4Accessor(tangents)
+	uv0Acc := b.addVec2Accessor(uv0)
+	uv1Acc := b.addVec2Accessor(uv1)
+	colorAcc := b.addVec4Accessor(colors)
+
+	var m mesh
+	cursor := 0
+	for i, s := range rn.Sections {
+		count := int(s.TriangleCount)
+		if cursor+count > len(rn.Triangles) {
+			return -1, fmt.Errorf("section %d wants %d triangles, only %d remain", i, count, len(rn.Triangles)-cursor)
+		}
+
+		indices := make([]uint32, 0, count*3)
+		for _, tri := range rn.Triangles[cursor : cursor+count] {
+			indices = append(indices, tri.T[0], tri.T[1], tri.T[2])
+		}
+		cursor += count
+		idxAcc := b.addIndexAccessor(indices)
+
+		var matIdx *int
+		if len(s.TextureName) > 0 || s.LightMapWidth > 0 {
+			mi := b.materialFor(s)
+			matIdx = &mi
+		}
+
+		m.Primitives = append(m.Primitives, primitive{
+			Attributes: map[string]int{
+				"POSITION":   posAcc,
+				"NORMAL":     normAcc,
+				"TANGENT":    tanAcc,
+				"TEXCOORD_0": uv0Acc,
+				"TEXCOORD_1": uv1Acc,
+				"COLOR_0":    colorAcc,
+			},
+			Indices:  idxAcc,
+			Material: matIdx,
+			Mode:     primitiveModeTriangles,
+		})
+	}
+
+	b.doc.Meshes = append(b.doc.Meshes, m)
+	return len(b.doc.Meshes) - 1, nil
+}
+
+// materialFor returns (creating if necessary) the material for a
+// section's base texture, with its lightmap baked into the emissive
+// channel against the section's second UV set.
+func (b *builder) materialFor(s lithdat.SECTION) int {
+	name := "lightmap-only"
+	if len(s.TextureName) > 0 {
+		name = s.TextureName[0]
+	}
+	if idx, ok := b.materialIndex[name]; ok {
+		return idx
+	}
+
+	mat := material{Name: name}
+	if len(s.TextureName) > 0 {
+		texIdx := b.textureFor(s.TextureName[0])
+		mat.PBRMetallicRoughness = &pbrMetallicRoughness{
+			BaseColorTexture: &textureInfo{Index: texIdx},
+			RoughnessFactor:  1,
+		}
+	}
+	if s.LightMapWidth > 0 && s.LightMapHeight > 0 {
+		lmIdx := b.textureFor(name + ".lightmap")
+		mat.EmissiveTexture = &textureInfo{Index: lmIdx, TexCoord: 1}
+		mat.EmissiveFactor = [3]float32{1, 1, 1}
+	}
+
+	b.doc.Materials = append(b.doc.Materials, mat)
+	idx := len(b.doc.Materials) - 1
+	b.materialIndex[name] = idx
+	return idx
+}
+
+func (b *builder) textureFor(name string) int {
+	if idx, ok := b.textureIndex[name]; ok {
+		return idx
+	}
+	b.doc.Images = append(b.doc.Images, image{URI: textureURI(name)})
+	imgIdx := len(b.doc.Images) - 1
+	b.doc.Textures = append(b.doc.Textures, texture{Source: imgIdx})
+	texIdx := len(b.doc.Textures) - 1
+	b.textureIndex[name] = texIdx
+	return texIdx
+}
+
+// textureURI maps a Lithtech texture name (typically a .dtx path) to
+// the external image this exporter expects it to have been converted
+// to; it doesn't embed texture data itself.
+func textureURI(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	return name + ".png"
+}
+
+// lightClasses are the WORLDOBJECT types treated as punctual lights.
+var lightClasses = map[string]string{
+	"Light":      "point",
+	"PointLight": "point",
+}
+
+func (b *builder) buildLightNodes(objects []lithdat.WORLDOBJECT) ([]int, error) {
+	var roots []int
+	for _, obj := range objects {
+		kind, ok := lightClasses[obj.ObjectType]
+		if !ok {
+			continue
+		}
+
+		var pos lithdat.VEC3
+		var color lithdat.COLOR = lithdat.COLOR{R: 1, G: 1, B: 1}
+		var radius float32
+		for _, p := range obj.Properties {
+			switch p.Name {
+			case "Pos":
+				pos = p.DataVEC3
+			case "LightColor":
+				color = p.DataCOLOR
+			case "LightRadius":
+				radius = p.DataFloat
+			}
+		}
+
+		light := punctualLight{Type: kind, Color: [3]float32{color.R, color.G, color.B}, Range: radius}
+		if b.doc.Extensions == nil {
+			b.doc.Extensions = &extensions{Lights: &lightsPunctual{}}
+			b.doc.ExtUsed = append(b.doc.ExtUsed, "KHR_lights_punctual")
+		}
+		b.doc.Extensions.Lights.Lights = append(b.doc.Extensions.Lights.Lights, light)
+		lightIdx := len(b.doc.Extensions.Lights.Lights) - 1
+
+		n := node{
+			Translation: vec3Array(pos),
+			Extensions:  &nodeExt{Light: &lightRef{Light: lightIdx}},
+		}
+		b.doc.Nodes = append(b.doc.Nodes, n)
+		roots = append(roots, len(b.doc.Nodes)-1)
+	}
+	return roots, nil
+}
+
+func vec3Array(v lithdat.VEC3) [3]float32 { return [3]float32{v.X, v.Y, v.Z} }
+
+func unpackColor(c int32) [4]float32 {
+	u := uint32(c)
+	return [4]float32{
+		float32(u&0xFF) / 255,
+		float32((u>>8)&0xFF) / 255,
+		float32((u>>16)&0xFF) / 255,
+		float32((u>>24)&0xFF) / 255,
+	}
+}
+
+func (b *builder) addBufferView(data []byte, target int) int {
+	offset := b.bin.Len()
+	b.bin.Write(data)
+	for b.bin.Len()%4 != 0 {
+		b.bin.WriteByte(0)
+	}
+	b.doc.BufferViews = append(b.doc.BufferViews, bufferView{
+		ByteOffset: offset,
+		ByteLength: len(data),
+		Target:     target,
+	})
+	return len(b.doc.BufferViews) - 1
+}
+
+func (b *builder) addVec3Accessor(data []lithdat.VEC3) int {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, data)
+	bv := b.addBufferView(buf.Bytes(), targetArrayBuffer)
+	b.doc.Accessors = append(b.doc.Accessors, accessor{
+		BufferView: bv, ComponentType: componentTypeFloat, Count: len(data), Type: accessorVec3,
+	})
+	return len(b.doc.Accessors) - 1
+}
+
+func (b *builder) addVec3PositionAccessor(data []lithdat.VEC3) int {
+	idx := b.addVec3Accessor(data)
+	if len(data) == 0 {
+		return idx
+	}
+	min, max := data[0], data[0]
+	for _, v := range data[1:] {
+		min = lithdat.VEC3{X: minf(min.X, v.X), Y: minf(min.Y, v.Y), Z: minf(min.Z, v.Z)}
+		max = lithdat.VEC3{X: maxf(max.X, v.X), Y: maxf(max.Y, v.Y), Z: maxf(max.Z, v.Z)}
+	}
+	b.doc.Accessors[idx].Min = []float32{min.X, min.Y, min.Z}
+	b.doc.Accessors[idx].Max = []float32{max.X, max.Y, max.Z}
+	return idx
+}
+
+func (b *builder) addVec2Accessor(data []lithdat.VEC2) int {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, data)
+	bv := b.addBufferView(buf.Bytes(), targetArrayBuffer)
+	b.doc.Accessors = append(b.doc.Accessors, accessor{
+		BufferView: bv, ComponentType: componentTypeFloat, Count: len(data), Type: accessorVec2,
+	})
+	return len(b.doc.Accessors) - 1
+}
+
+func (b *builder) addVec4Accessor(data [][4]float32) int {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, data)
+	bv := b.addBufferView(buf.Bytes(), targetArrayBuffer)
+	b.doc.Accessors = append(b.doc.Accessors, accessor{
+		BufferView: bv, ComponentType: componentTypeFloat, Count: len(data), Type: accessorVec4,
+	})
+	return len(b.doc.Accessors) - 1
+}
+
+func (b *builder) addIndexAccessor(data []uint32) int {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, data)
+	bv := b.addBufferView(buf.Bytes(), targetElementArrayBuffer)
+	b.doc.Accessors = append(b.doc.Accessors, accessor{
+		BufferView: bv, ComponentType: componentTypeUnsignedInt, Count: len(data), Type: accessorScal,
+	})
+	return len(b.doc.Accessors) - 1
+}
+
+func minf(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxf(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// glb chunk type codes, per the glTF 2.0 binary container spec.
+const (
+	chunkTypeJSON = 0x4E4F534A
+	chunkTypeBIN  = 0x004E4942
+	glbMagic      = 0x46546C67
+	glbVersion    = 2
+)
+
+// writeGLB encodes doc and bin as a .glb: a 12-byte header followed by
+// a JSON chunk (padded with spaces) and a BIN chunk (padded with
+// zeros), each 4-byte aligned as the spec requires.
+func writeGLB(doc *document, bin []byte, out io.Writer) error {
+	jsonBytes, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("encoding glTF JSON: %w", err)
+	}
+	for len(jsonBytes)%4 != 0 {
+		jsonBytes = append(jsonBytes, ' ')
+	}
+	for len(bin)%4 != 0 {
+		bin = append(bin, 0)
+	}
+
+	totalLen := 12 + 8 + len(jsonBytes) + 8 + len(bin)
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(glbMagic))
+	binary.Write(&buf, binary.LittleEndian, uint32(glbVersion))
+	binary.Write(&buf, binary.LittleEndian, uint32(totalLen))
+
+	binary.Write(&buf, binary.LittleEndian, uint32(len(jsonBytes)))
+	binary.Write(&buf, binary.LittleEndian, uint32(chunkTypeJSON))
+	buf.Write(jsonBytes)
+
+	binary.Write(&buf, binary.LittleEndian, uint32(len(bin)))
+	binary.Write(&buf, binary.LittleEndian, uint32(chunkTypeBIN))
+	buf.Write(bin)
+
+	_, err = out.Write(buf.Bytes())
+	return err
+}
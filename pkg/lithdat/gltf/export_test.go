@@ -0,0 +1,34 @@
+package gltf
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/Jldevictoria/lithdat/pkg/lithdat"
+)
+
+func TestExport(t *testing.T) {
+	f, err := lithdat.Open(filepath.Join("..", "testdata", "minimal.dat"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	w, err := f.World()
+	if err != nil {
+		t.Fatalf("World: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Export(w, &buf); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	if buf.Len() < 12 {
+		t.Fatalf("Export produced %d bytes, want at least a 12-byte glb header", buf.Len())
+	}
+	if got := string(buf.Bytes()[:4]); got != "glTF" {
+		t.Fatalf("glb magic = %q, want %q", got, "glTF")
+	}
+}
@@ -0,0 +1,83 @@
+package lithdat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// LightGrid returns the world's coarse lightgrid, decoding it from
+// LightgridPos on first call. The grid's voxel color data is left RLE
+// compressed in LgData; see LIGHTGRID.Decode.
+func (f *File) LightGrid() (LIGHTGRID, error) {
+	f.lightGridOnce.Do(func() {
+		f.lightGrid, f.lightGridErr = f.readLightGrid()
+	})
+	return f.lightGrid, f.lightGridErr
+}
+
+func (f *File) readLightGrid() (LIGHTGRID, error) {
+	r := f.sectionReader(f.Header.LightgridPos)
+
+	var lg LIGHTGRID
+	if err := binary.Read(r, binary.LittleEndian, &lg.LookupStart); err != nil {
+		return lg, fmt.Errorf("lithdat: reading lightgrid lookup start: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &lg.BlockSize); err != nil {
+		return lg, fmt.Errorf("lithdat: reading lightgrid block size: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &lg.LookupSize); err != nil {
+		return lg, fmt.Errorf("lithdat: reading lightgrid lookup size: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, &lg.LgDataLen); err != nil {
+		return lg, fmt.Errorf("lithdat: reading lightgrid data length: %w", err)
+	}
+	lg.LgData = make([]byte, lg.LgDataLen)
+	if _, err := io.ReadFull(r, lg.LgData); err != nil {
+		return lg, fmt.Errorf("lithdat: reading lightgrid data: %w", err)
+	}
+	return lg, nil
+}
+
+func writeLightGrid(w io.Writer, lg *LIGHTGRID) error {
+	if err := binary.Write(w, binary.LittleEndian, lg.LookupStart); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, lg.BlockSize); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, lg.LookupSize); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(lg.LgData))); err != nil {
+		return err
+	}
+	if _, err := w.Write(lg.LgData); err != nil {
+		return fmt.Errorf("writing lightgrid data: %w", err)
+	}
+	return nil
+}
+
+// Decode expands LgData into one color per voxel, in row-major
+// (x, then y, then z) order over a LookupSize[0] x LookupSize[1] x
+// LookupSize[2] grid of BlockSize-sized cells. Each voxel is stored as
+// a single intensity byte, which Decode replicates across all three
+// color channels.
+func (lg LIGHTGRID) Decode() ([]COLOR, error) {
+	want := int(lg.LookupSize[0]) * int(lg.LookupSize[1]) * int(lg.LookupSize[2])
+	data, err := decodeRLE(lg.LgData)
+	if err != nil {
+		return nil, fmt.Errorf("lithdat: decoding lightgrid: %w", err)
+	}
+	if len(data) != want {
+		return nil, fmt.Errorf("lithdat: lightgrid decoded to %d voxels, want %d (%dx%dx%d)",
+			len(data), want, lg.LookupSize[0], lg.LookupSize[1], lg.LookupSize[2])
+	}
+
+	colors := make([]COLOR, want)
+	for i, b := range data {
+		v := float32(b) / 255
+		colors[i] = COLOR{R: v, G: v, B: v}
+	}
+	return colors, nil
+}
@@ -0,0 +1,83 @@
+// Package obj exports a parsed Lithtech WORLD's render geometry as a
+// Wavefront OBJ + MTL pair: positions, UVs, and normals only, with one
+// material per base texture. Unlike pkg/lithdat/gltf it carries no
+// lights, lightmaps, or node hierarchy.
+package obj
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Jldevictoria/lithdat/pkg/lithdat"
+)
+
+// Export walks w's render tree and writes a Wavefront OBJ file to obj,
+// referencing mtlName as its material library, and writes the matching
+// MTL file to mtl.
+func Export(w *lithdat.WORLD, objOut, mtlOut io.Writer, mtlName string) error {
+	materials := map[string]bool{}
+	var order []string
+
+	fmt.Fprintf(objOut, "# exported by lithdat\nmtllib %s\n", mtlName)
+
+	var vertexBase int
+	for i, rn := range w.RenderData.RenderNodes {
+		if len(rn.Vertices) == 0 {
+			continue
+		}
+		fmt.Fprintf(objOut, "o rendernode%d\n", i)
+		for _, v := range rn.Vertices {
+			fmt.Fprintf(objOut, "v %g %g %g\n", v.VPos.X, v.VPos.Y, v.VPos.Z)
+			fmt.Fprintf(objOut, "vt %g %g\n", v.Uv0.X, v.Uv0.Y)
+			fmt.Fprintf(objOut, "vn %g %g %g\n", v.VNormal.X, v.VNormal.Y, v.VNormal.Z)
+		}
+
+		cursor := 0
+		for si, s := range rn.Sections {
+			count := int(s.TriangleCount)
+			if cursor+count > len(rn.Triangles) {
+				return fmt.Errorf("lithdat/obj: render node %d section %d wants %d triangles, only %d remain",
+					i, si, count, len(rn.Triangles)-cursor)
+			}
+
+			name := materialName(s)
+			if !materials[name] {
+				materials[name] = true
+				order = append(order, name)
+			}
+			fmt.Fprintf(objOut, "usemtl %s\n", name)
+
+			for _, tri := range rn.Triangles[cursor : cursor+count] {
+				a := vertexBase + int(tri.T[0]) + 1
+				b := vertexBase + int(tri.T[1]) + 1
+				c := vertexBase + int(tri.T[2]) + 1
+				fmt.Fprintf(objOut, "f %d/%d/%d %d/%d/%d %d/%d/%d\n", a, a, a, b, b, b, c, c, c)
+			}
+			cursor += count
+		}
+		vertexBase += len(rn.Vertices)
+	}
+
+	for _, name := range order {
+		fmt.Fprintf(mtlOut, "newmtl %s\n", name)
+		if name != "lightmap-only" {
+			fmt.Fprintf(mtlOut, "map_Kd %s\n", textureURI(name))
+		}
+	}
+	return nil
+}
+
+func materialName(s lithdat.SECTION) string {
+	if len(s.TextureName) == 0 {
+		return "lightmap-only"
+	}
+	return s.TextureName[0]
+}
+
+func textureURI(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	return name + ".png"
+}
@@ -0,0 +1,35 @@
+package obj
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jldevictoria/lithdat/pkg/lithdat"
+)
+
+func TestExport(t *testing.T) {
+	f, err := lithdat.Open(filepath.Join("..", "testdata", "minimal.dat"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	w, err := f.World()
+	if err != nil {
+		t.Fatalf("World: %v", err)
+	}
+
+	var objBuf, mtlBuf bytes.Buffer
+	if err := Export(w, &objBuf, &mtlBuf, "scene.mtl"); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	if !strings.Contains(objBuf.String(), "mtllib scene.mtl") {
+		t.Errorf("obj output missing mtllib directive:\n%s", objBuf.String())
+	}
+	if !strings.Contains(mtlBuf.String(), "newmtl wall.dtx") {
+		t.Errorf("mtl output missing expected material:\n%s", mtlBuf.String())
+	}
+}
@@ -0,0 +1,76 @@
+package lithdat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// CollisionPolies returns the world's collision geometry, decoding it
+// from CollisionDataPos on first call.
+func (f *File) CollisionPolies() ([]POLYGON, error) {
+	f.collisionPoliesOnce.Do(func() {
+		f.collisionPolies, f.collisionPoliesErr = f.readPolygons(f.Header.CollisionDataPos)
+	})
+	return f.collisionPolies, f.collisionPoliesErr
+}
+
+// ParticleBlockers returns the polygons that block particle effects,
+// decoding them from ParticleBlockerDataPos on first call.
+func (f *File) ParticleBlockers() ([]POLYGON, error) {
+	f.particleBlockersOnce.Do(func() {
+		f.particleBlockers, f.particleBlockersErr = f.readPolygons(f.Header.ParticleBlockerDataPos)
+	})
+	return f.particleBlockers, f.particleBlockersErr
+}
+
+func (f *File) readPolygons(offset uint32) ([]POLYGON, error) {
+	r := f.sectionReader(offset)
+
+	var count uint32
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, fmt.Errorf("lithdat: reading polygon count: %w", err)
+	}
+
+	polies := make([]POLYGON, count)
+	for i := range polies {
+		p, err := readPolygon(r)
+		if err != nil {
+			return nil, fmt.Errorf("lithdat: reading polygon %d: %w", i, err)
+		}
+		polies[i] = p
+	}
+	return polies, nil
+}
+
+func readPolygon(r io.Reader) (POLYGON, error) {
+	var p POLYGON
+	if err := binary.Read(r, binary.LittleEndian, &p.Plane); err != nil {
+		return p, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &p.VertLen); err != nil {
+		return p, err
+	}
+	p.VertPos = make([]VEC3, p.VertLen)
+	for i := range p.VertPos {
+		if err := binary.Read(r, binary.LittleEndian, &p.VertPos[i]); err != nil {
+			return p, fmt.Errorf("reading vertex %d: %w", i, err)
+		}
+	}
+	return p, nil
+}
+
+func writePolygon(w io.Writer, p POLYGON) error {
+	if err := binary.Write(w, binary.LittleEndian, p.Plane); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(p.VertPos))); err != nil {
+		return err
+	}
+	for i, v := range p.VertPos {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return fmt.Errorf("writing vertex %d: %w", i, err)
+		}
+	}
+	return nil
+}
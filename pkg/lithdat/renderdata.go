@@ -0,0 +1,618 @@
+package lithdat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+)
+
+// RenderData returns the world's renderable geometry: the main render
+// tree, the per-world-model render nodes, and the world light groups.
+// It is decoded from RenderDataPos on first call.
+func (f *File) RenderData() (RENDERDATA, error) {
+	f.renderDataOnce.Do(func() {
+		f.renderData, f.renderDataErr = f.readRenderData()
+	})
+	return f.renderData, f.renderDataErr
+}
+
+func (f *File) readRenderData() (RENDERDATA, error) {
+	r := f.sectionReader(f.Header.RenderDataPos)
+
+	var rd RENDERDATA
+	if err := binary.Read(r, binary.LittleEndian, &rd.RenderTreeNodesLen); err != nil {
+		return rd, fmt.Errorf("lithdat: reading render tree node count: %w", err)
+	}
+	rd.RenderNodes = make([]RENDERNODE, rd.RenderTreeNodesLen)
+	for i := range rd.RenderNodes {
+		n, err := readRenderNode(r)
+		if err != nil {
+			return rd, fmt.Errorf("lithdat: reading render node %d: %w", i, err)
+		}
+		rd.RenderNodes[i] = n
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &rd.WorldModelNodesLen); err != nil {
+		return rd, fmt.Errorf("lithdat: reading world model render node count: %w", err)
+	}
+	rd.WorldModelRenderNotes = make([]WMRENDERNODE, rd.WorldModelNodesLen)
+	for i := range rd.WorldModelRenderNotes {
+		n, err := readWMRenderNode(r)
+		if err != nil {
+			return rd, fmt.Errorf("lithdat: reading world model render node %d: %w", i, err)
+		}
+		rd.WorldModelRenderNotes[i] = n
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &rd.LightGroupsLen); err != nil {
+		return rd, fmt.Errorf("lithdat: reading light group count: %w", err)
+	}
+	rd.LightGroups = make([]WORLDLIGHTGROUP, rd.LightGroupsLen)
+	for i := range rd.LightGroups {
+		g, err := readWorldLightGroup(r)
+		if err != nil {
+			return rd, fmt.Errorf("lithdat: reading light group %d: %w", i, err)
+		}
+		rd.LightGroups[i] = g
+	}
+	return rd, nil
+}
+
+func readRenderNode(r io.Reader) (RENDERNODE, error) {
+	var n RENDERNODE
+	if err := binary.Read(r, binary.LittleEndian, &n.VCenter); err != nil {
+		return n, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &n.VHalfDims); err != nil {
+		return n, err
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &n.SectionLen); err != nil {
+		return n, err
+	}
+	n.Sections = make([]SECTION, n.SectionLen)
+	for i := range n.Sections {
+		s, err := readSection(r)
+		if err != nil {
+			return n, fmt.Errorf("reading section %d: %w", i, err)
+		}
+		n.Sections[i] = s
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &n.VericesLen); err != nil {
+		return n, err
+	}
+	n.Vertices = make([]VERTEX, n.VericesLen)
+	if err := binary.Read(r, binary.LittleEndian, n.Vertices); err != nil {
+		return n, fmt.Errorf("reading vertices: %w", err)
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &n.TrianglesLen); err != nil {
+		return n, err
+	}
+	n.Triangles = make([]TRIANGLE, n.TrianglesLen)
+	if err := binary.Read(r, binary.LittleEndian, n.Triangles); err != nil {
+		return n, fmt.Errorf("reading triangles: %w", err)
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &n.SkyPortalLen); err != nil {
+		return n, err
+	}
+	n.Skyportals = make([]SKYPORTAL, n.SkyPortalLen)
+	for i := range n.Skyportals {
+		sp, err := readSkyPortal(r)
+		if err != nil {
+			return n, fmt.Errorf("reading sky portal %d: %w", i, err)
+		}
+		n.Skyportals[i] = sp
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &n.OccluderLen); err != nil {
+		return n, err
+	}
+	n.Occluders = make([]OCCLUDER, n.OccluderLen)
+	for i := range n.Occluders {
+		o, err := readOccluder(r)
+		if err != nil {
+			return n, fmt.Errorf("reading occluder %d: %w", i, err)
+		}
+		n.Occluders[i] = o
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &n.LightGroupLen); err != nil {
+		return n, err
+	}
+	n.LightGroups = make([]LIGHTGROUP, n.LightGroupLen)
+	for i := range n.LightGroups {
+		lg, err := readLightGroup(r)
+		if err != nil {
+			return n, fmt.Errorf("reading light group %d: %w", i, err)
+		}
+		n.LightGroups[i] = lg
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &n.ChildFlags); err != nil {
+		return n, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &n.ChildNodeIndicies); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// readSection reads a SECTION. Texture names aren't count-prefixed in
+// the format; they're read as a run of length-prefixed strings
+// terminated by an empty one, matching the (up to four) texture stages
+// Lithtech sections carry.
+func readSection(r io.Reader) (SECTION, error) {
+	var s SECTION
+	for {
+		name, err := readLithSTRING(r)
+		if err != nil {
+			return s, fmt.Errorf("reading texture name: %w", err)
+		}
+		if name == "" {
+			break
+		}
+		s.TextureName = append(s.TextureName, name)
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &s.ShaderCode); err != nil {
+		return s, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &s.TriangleCount); err != nil {
+		return s, err
+	}
+
+	var err error
+	if s.TextureEffect, err = readLithSTRING(r); err != nil {
+		return s, fmt.Errorf("reading texture effect: %w", err)
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &s.LightMapWidth); err != nil {
+		return s, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &s.LightMapHeight); err != nil {
+		return s, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &s.LightMapSize); err != nil {
+		return s, err
+	}
+	s.LightMapData = make([]byte, s.LightMapSize)
+	if _, err := io.ReadFull(r, s.LightMapData); err != nil {
+		return s, fmt.Errorf("reading lightmap data: %w", err)
+	}
+	return s, nil
+}
+
+func readVerticesPos(r io.Reader) (VERTICESPOS, error) {
+	var vp VERTICESPOS
+	if err := binary.Read(r, binary.LittleEndian, &vp.Len); err != nil {
+		return vp, err
+	}
+	vp.Data = make([]VEC3, vp.Len)
+	for i := range vp.Data {
+		if err := binary.Read(r, binary.LittleEndian, &vp.Data[i]); err != nil {
+			return vp, fmt.Errorf("reading vertex %d: %w", i, err)
+		}
+	}
+	return vp, nil
+}
+
+func readSkyPortal(r io.Reader) (SKYPORTAL, error) {
+	var sp SKYPORTAL
+	var err error
+	if sp.VerticesPos, err = readVerticesPos(r); err != nil {
+		return sp, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &sp.Plane); err != nil {
+		return sp, err
+	}
+	return sp, nil
+}
+
+func readOccluder(r io.Reader) (OCCLUDER, error) {
+	var o OCCLUDER
+	var err error
+	if o.VerticesPos, err = readVerticesPos(r); err != nil {
+		return o, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &o.Plane); err != nil {
+		return o, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &o.Other); err != nil {
+		return o, err
+	}
+	return o, nil
+}
+
+func readLightGroup(r io.Reader) (LIGHTGROUP, error) {
+	var lg LIGHTGROUP
+	var err error
+	if lg.Name, err = readLithSTRING(r); err != nil {
+		return lg, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &lg.VColor); err != nil {
+		return lg, err
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &lg.NIntensitySize); err != nil {
+		return lg, err
+	}
+	lg.ZeroCompressedIntensityData = make([]byte, lg.NIntensitySize)
+	if _, err := io.ReadFull(r, lg.ZeroCompressedIntensityData); err != nil {
+		return lg, fmt.Errorf("reading vertex intensity data: %w", err)
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &lg.SectionLMLen); err != nil {
+		return lg, err
+	}
+	lg.SectionLM = make([]SECTIONLM, lg.SectionLMLen)
+	for i := range lg.SectionLM {
+		slm, err := readSectionLM(r)
+		if err != nil {
+			return lg, fmt.Errorf("reading section lightmap %d: %w", i, err)
+		}
+		lg.SectionLM[i] = slm
+	}
+	return lg, nil
+}
+
+// VertexIntensities expands the light group's zero-compressed per-vertex
+// intensities. vertexCount must be the vertex count of the RENDERNODE
+// the light group belongs to; the decoded output is asserted to match
+// it, since nothing in LIGHTGROUP itself records it.
+func (lg LIGHTGROUP) VertexIntensities(vertexCount int) ([]byte, error) {
+	data, err := decodeZeroCompressed(lg.ZeroCompressedIntensityData)
+	if err != nil {
+		return nil, fmt.Errorf("lithdat: decoding vertex intensities: %w", err)
+	}
+	if len(data) != vertexCount {
+		return nil, fmt.Errorf("lithdat: light group %q decoded %d vertex intensities, want %d", lg.Name, len(data), vertexCount)
+	}
+	return data, nil
+}
+
+func readSectionLM(r io.Reader) (SECTIONLM, error) {
+	var slm SECTIONLM
+	if err := binary.Read(r, binary.LittleEndian, &slm.SubLMLen); err != nil {
+		return slm, err
+	}
+	slm.Sublm = make([]SUBLM, slm.SubLMLen)
+	for i := range slm.Sublm {
+		s, err := readSubLM(r)
+		if err != nil {
+			return slm, fmt.Errorf("reading sub lightmap %d: %w", i, err)
+		}
+		slm.Sublm[i] = s
+	}
+	return slm, nil
+}
+
+func readSubLM(r io.Reader) (SUBLM, error) {
+	var s SUBLM
+	for _, f := range []*uint32{&s.Left, &s.Top, &s.Width, &s.Height, &s.DataLen} {
+		if err := binary.Read(r, binary.LittleEndian, f); err != nil {
+			return s, err
+		}
+	}
+	s.Data = make([]byte, s.DataLen)
+	if _, err := io.ReadFull(r, s.Data); err != nil {
+		return s, fmt.Errorf("reading sub lightmap data: %w", err)
+	}
+	return s, nil
+}
+
+// Decode expands a sublightmap's RLE-compressed Data into a Width x
+// Height grayscale image, one intensity byte per pixel.
+func (s SUBLM) Decode() (image.Image, error) {
+	data, err := decodeRLE(s.Data)
+	if err != nil {
+		return nil, fmt.Errorf("lithdat: decoding sublightmap: %w", err)
+	}
+	want := int(s.Width) * int(s.Height)
+	if len(data) != want {
+		return nil, fmt.Errorf("lithdat: sublightmap decoded to %d bytes, want %d (%dx%d)", len(data), want, s.Width, s.Height)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, int(s.Width), int(s.Height)))
+	copy(img.Pix, data)
+	return img, nil
+}
+
+func readWMRenderNode(r io.Reader) (WMRENDERNODE, error) {
+	var n WMRENDERNODE
+	var err error
+	if n.Name, err = readLithSTRING(r); err != nil {
+		return n, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &n.NodesLen); err != nil {
+		return n, err
+	}
+	n.Nodes = make([]RENDERNODE, n.NodesLen)
+	for i := range n.Nodes {
+		rn, err := readRenderNode(r)
+		if err != nil {
+			return n, fmt.Errorf("reading render node %d: %w", i, err)
+		}
+		n.Nodes[i] = rn
+	}
+	if err := binary.Read(r, binary.LittleEndian, &n.NoChildFlag); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+func readWorldLightGroup(r io.Reader) (WORLDLIGHTGROUP, error) {
+	var g WORLDLIGHTGROUP
+	var err error
+	if g.Name, err = readLithSTRING(r); err != nil {
+		return g, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &g.Color); err != nil {
+		return g, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &g.VOffset); err != nil {
+		return g, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &g.VSize); err != nil {
+		return g, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &g.DataLen); err != nil {
+		return g, err
+	}
+	g.Data = make([]byte, g.DataLen)
+	if _, err := io.ReadFull(r, g.Data); err != nil {
+		return g, fmt.Errorf("reading light group data: %w", err)
+	}
+	return g, nil
+}
+
+func writeRenderData(w io.Writer, rd *RENDERDATA) error {
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(rd.RenderNodes))); err != nil {
+		return err
+	}
+	for i, n := range rd.RenderNodes {
+		if err := writeRenderNode(w, n); err != nil {
+			return fmt.Errorf("writing render node %d: %w", i, err)
+		}
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(rd.WorldModelRenderNotes))); err != nil {
+		return err
+	}
+	for i, n := range rd.WorldModelRenderNotes {
+		if err := writeWMRenderNode(w, n); err != nil {
+			return fmt.Errorf("writing world model render node %d: %w", i, err)
+		}
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(rd.LightGroups))); err != nil {
+		return err
+	}
+	for i, g := range rd.LightGroups {
+		if err := writeWorldLightGroup(w, g); err != nil {
+			return fmt.Errorf("writing light group %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func writeRenderNode(w io.Writer, n RENDERNODE) error {
+	if err := binary.Write(w, binary.LittleEndian, n.VCenter); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, n.VHalfDims); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(n.Sections))); err != nil {
+		return err
+	}
+	for i, s := range n.Sections {
+		if err := writeSection(w, s); err != nil {
+			return fmt.Errorf("writing section %d: %w", i, err)
+		}
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(n.Vertices))); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, n.Vertices); err != nil {
+		return fmt.Errorf("writing vertices: %w", err)
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(n.Triangles))); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, n.Triangles); err != nil {
+		return fmt.Errorf("writing triangles: %w", err)
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(n.Skyportals))); err != nil {
+		return err
+	}
+	for i, sp := range n.Skyportals {
+		if err := writeSkyPortal(w, sp); err != nil {
+			return fmt.Errorf("writing sky portal %d: %w", i, err)
+		}
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(n.Occluders))); err != nil {
+		return err
+	}
+	for i, o := range n.Occluders {
+		if err := writeOccluder(w, o); err != nil {
+			return fmt.Errorf("writing occluder %d: %w", i, err)
+		}
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(n.LightGroups))); err != nil {
+		return err
+	}
+	for i, lg := range n.LightGroups {
+		if err := writeLightGroup(w, lg); err != nil {
+			return fmt.Errorf("writing light group %d: %w", i, err)
+		}
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, n.ChildFlags); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, n.ChildNodeIndicies); err != nil {
+		return err
+	}
+	return nil
+}
+
+func writeSection(w io.Writer, s SECTION) error {
+	for _, name := range s.TextureName {
+		if err := writeLithSTRING(w, name); err != nil {
+			return fmt.Errorf("writing texture name: %w", err)
+		}
+	}
+	if err := writeLithSTRING(w, ""); err != nil {
+		return fmt.Errorf("writing texture name terminator: %w", err)
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, s.ShaderCode); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, s.TriangleCount); err != nil {
+		return err
+	}
+	if err := writeLithSTRING(w, s.TextureEffect); err != nil {
+		return fmt.Errorf("writing texture effect: %w", err)
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, s.LightMapWidth); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, s.LightMapHeight); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(s.LightMapData))); err != nil {
+		return err
+	}
+	if _, err := w.Write(s.LightMapData); err != nil {
+		return fmt.Errorf("writing lightmap data: %w", err)
+	}
+	return nil
+}
+
+func writeVerticesPos(w io.Writer, vp VERTICESPOS) error {
+	if err := binary.Write(w, binary.LittleEndian, uint8(len(vp.Data))); err != nil {
+		return err
+	}
+	for i, v := range vp.Data {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return fmt.Errorf("writing vertex %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func writeSkyPortal(w io.Writer, sp SKYPORTAL) error {
+	if err := writeVerticesPos(w, sp.VerticesPos); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, sp.Plane)
+}
+
+func writeOccluder(w io.Writer, o OCCLUDER) error {
+	if err := writeVerticesPos(w, o.VerticesPos); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, o.Plane); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, o.Other)
+}
+
+func writeLightGroup(w io.Writer, lg LIGHTGROUP) error {
+	if err := writeLithSTRING(w, lg.Name); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, lg.VColor); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(lg.ZeroCompressedIntensityData))); err != nil {
+		return err
+	}
+	if _, err := w.Write(lg.ZeroCompressedIntensityData); err != nil {
+		return fmt.Errorf("writing vertex intensity data: %w", err)
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(lg.SectionLM))); err != nil {
+		return err
+	}
+	for i, slm := range lg.SectionLM {
+		if err := writeSectionLM(w, slm); err != nil {
+			return fmt.Errorf("writing section lightmap %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func writeSectionLM(w io.Writer, slm SECTIONLM) error {
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(slm.Sublm))); err != nil {
+		return err
+	}
+	for i, s := range slm.Sublm {
+		if err := writeSubLM(w, s); err != nil {
+			return fmt.Errorf("writing sub lightmap %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func writeSubLM(w io.Writer, s SUBLM) error {
+	for _, f := range []uint32{s.Left, s.Top, s.Width, s.Height, uint32(len(s.Data))} {
+		if err := binary.Write(w, binary.LittleEndian, f); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write(s.Data); err != nil {
+		return fmt.Errorf("writing sub lightmap data: %w", err)
+	}
+	return nil
+}
+
+func writeWMRenderNode(w io.Writer, n WMRENDERNODE) error {
+	if err := writeLithSTRING(w, n.Name); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(n.Nodes))); err != nil {
+		return err
+	}
+	for i, rn := range n.Nodes {
+		if err := writeRenderNode(w, rn); err != nil {
+			return fmt.Errorf("writing render node %d: %w", i, err)
+		}
+	}
+	return binary.Write(w, binary.LittleEndian, n.NoChildFlag)
+}
+
+func writeWorldLightGroup(w io.Writer, g WORLDLIGHTGROUP) error {
+	if err := writeLithSTRING(w, g.Name); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, g.Color); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, g.VOffset); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, g.VSize); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(g.Data))); err != nil {
+		return err
+	}
+	if _, err := w.Write(g.Data); err != nil {
+		return fmt.Errorf("writing light group data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package lithdat
+
+import "fmt"
+
+// decodeRLE expands a byte stream RLE-compressed the way sublightmaps
+// and the lightgrid are: a literal byte copies itself to the output,
+// while a 0xFF byte introduces a run — the next two bytes are a repeat
+// count and the byte value to repeat that many times.
+func decodeRLE(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		b := data[i]
+		if b != 0xFF {
+			out = append(out, b)
+			i++
+			continue
+		}
+		if i+2 >= len(data) {
+			return nil, fmt.Errorf("truncated RLE run at offset %d", i)
+		}
+		runCount, color := data[i+1], data[i+2]
+		for j := uint8(0); j < runCount; j++ {
+			out = append(out, color)
+		}
+		i += 3
+	}
+	return out, nil
+}
+
+// decodeZeroCompressed expands a byte stream zero-compressed the way
+// vertex intensities are: a non-zero byte is a literal, while a zero
+// byte introduces a run — the following byte is a count of zero bytes
+// to emit.
+func decodeZeroCompressed(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		b := data[i]
+		if b != 0 {
+			out = append(out, b)
+			i++
+			continue
+		}
+		if i+1 >= len(data) {
+			return nil, fmt.Errorf("truncated zero-compressed run at offset %d", i)
+		}
+		count := data[i+1]
+		for j := uint8(0); j < count; j++ {
+			out = append(out, 0)
+		}
+		i += 2
+	}
+	return out, nil
+}
@@ -0,0 +1,133 @@
+package lithdat
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeRLE(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []byte
+		want    []byte
+		wantErr bool
+	}{
+		{name: "empty", in: nil, want: []byte{}},
+		{name: "all literal", in: []byte{1, 2, 3}, want: []byte{1, 2, 3}},
+		{name: "single run", in: []byte{0xFF, 4, 9}, want: []byte{9, 9, 9, 9}},
+		{name: "run and literals", in: []byte{1, 0xFF, 3, 5, 2}, want: []byte{1, 5, 5, 5, 2}},
+		{name: "zero run count", in: []byte{0xFF, 0, 9, 7}, want: []byte{7}},
+		{name: "truncated run", in: []byte{1, 0xFF, 2}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeRLE(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeRLE(%v) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeRLE(%v) error: %v", tt.in, err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("decodeRLE(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeZeroCompressed(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []byte
+		want    []byte
+		wantErr bool
+	}{
+		{name: "empty", in: nil, want: []byte{}},
+		{name: "all literal", in: []byte{1, 2, 3}, want: []byte{1, 2, 3}},
+		{name: "single run", in: []byte{0, 3}, want: []byte{0, 0, 0}},
+		{name: "run and literals", in: []byte{5, 0, 2, 9}, want: []byte{5, 0, 0, 9}},
+		{name: "truncated run", in: []byte{1, 0}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeZeroCompressed(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeZeroCompressed(%v) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeZeroCompressed(%v) error: %v", tt.in, err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("decodeZeroCompressed(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLightGridDecode(t *testing.T) {
+	lg := LIGHTGRID{
+		LookupSize: [3]uint32{2, 1, 1},
+		LgData:     []byte{0xFF, 2, 255},
+	}
+	colors, err := lg.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := []COLOR{{R: 1, G: 1, B: 1}, {R: 1, G: 1, B: 1}}
+	if len(colors) != len(want) {
+		t.Fatalf("Decode returned %d colors, want %d", len(colors), len(want))
+	}
+	for i, c := range colors {
+		if c != want[i] {
+			t.Errorf("colors[%d] = %+v, want %+v", i, c, want[i])
+		}
+	}
+}
+
+func TestLightGridDecodeSizeMismatch(t *testing.T) {
+	lg := LIGHTGRID{
+		LookupSize: [3]uint32{2, 1, 1},
+		LgData:     []byte{1},
+	}
+	if _, err := lg.Decode(); err == nil {
+		t.Fatal("Decode: want error on voxel count mismatch, got nil")
+	}
+}
+
+func TestSubLMDecode(t *testing.T) {
+	s := SUBLM{
+		Width:  2,
+		Height: 2,
+		Data:   []byte{0xFF, 4, 128},
+	}
+	img, err := s.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() != 2 || bounds.Dy() != 2 {
+		t.Fatalf("Decode image size = %v, want 2x2", bounds)
+	}
+}
+
+func TestLightGroupVertexIntensities(t *testing.T) {
+	lg := LIGHTGROUP{ZeroCompressedIntensityData: []byte{5, 0, 2}}
+	got, err := lg.VertexIntensities(3)
+	if err != nil {
+		t.Fatalf("VertexIntensities: %v", err)
+	}
+	want := []byte{5, 0, 0}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("VertexIntensities = %v, want %v", got, want)
+	}
+
+	if _, err := lg.VertexIntensities(2); err == nil {
+		t.Fatal("VertexIntensities: want error on vertex count mismatch, got nil")
+	}
+}
@@ -1,11 +1,9 @@
-package main
+package lithdat
 
-import (
-	"encoding/binary"
-	"fmt"
-	"io"
-	"os"
-)
+// This file holds the on-disk structures for the Lithtech WORLD (.dat)
+// format, in the order they appear in a parsed file. Comments on fields
+// whose exact meaning is inferred rather than documented upstream are
+// marked accordingly.
 
 type SURFACE struct {
 	Flags        uint32
@@ -24,6 +22,21 @@ type VEC3 struct {
 	Z float32
 }
 
+// Dot returns the dot product of v and o.
+func (v VEC3) Dot(o VEC3) float32 {
+	return v.X*o.X + v.Y*o.Y + v.Z*o.Z
+}
+
+// Lerp linearly interpolates between v and o, t=0 returning v and t=1
+// returning o.
+func (v VEC3) Lerp(o VEC3, t float32) VEC3 {
+	return VEC3{
+		X: v.X + (o.X-v.X)*t,
+		Y: v.Y + (o.Y-v.Y)*t,
+		Z: v.Z + (o.Z-v.Z)*t,
+	}
+}
+
 type COLOR struct {
 	R float32
 	G float32
@@ -69,6 +82,13 @@ type WMPOLYGON struct {
 	VerticesIndicies []uint32
 }
 
+// NODE_IN and NODE_OUT are the sentinel child indices used by WMNODE to
+// mark a solid or empty leaf instead of another interior node.
+const (
+	NODE_IN  int32 = -1
+	NODE_OUT int32 = -2
+)
+
 type WMNODE struct {
 	PolyIndex         uint32
 	Zero              uint32   // unused leaves
@@ -239,6 +259,7 @@ type WORLDLIGHTGROUP struct {
 	// poly data is saved as part of the rendering data
 	VOffset VEC3
 	VSize   VEC3
+	DataLen uint32
 	Data    []byte
 }
 
@@ -273,7 +294,15 @@ type BLINDOBJECTDATA struct {
 	Data   []byte
 }
 
+// WORLD is the fully parsed contents of a Lithtech world file. It is
+// returned in one piece by File.World for callers that want everything
+// at once; File's section accessors populate the same shapes lazily and
+// are generally preferred since they decode only what's needed.
+//
+// A WORLD obtained from File.World can be round-tripped through
+// WriteTo to produce a byte-identical file.
 type WORLD struct {
+	Header          HEADER
 	WorldInfoStrLen uint32
 	WorldInfoStr    string
 	WorldExtentsMin VEC3
@@ -299,132 +328,3 @@ type WORLD struct {
 	// Render data
 	RenderData RENDERDATA
 }
-
-// uses the leading 16 bits to determine the size of a string to read,
-// reads into a buffer and then converts to a go string.
-func readLithSTRING(file *os.File, destination *string) {
-	var size uint16
-	_ = binary.Read(file, binary.LittleEndian, &size)
-	buf := make([]byte, size)
-	_ = binary.Read(file, binary.LittleEndian, &buf)
-	*destination = string(buf)
-}
-
-func main() {
-	filename := os.Args[1]
-	worldFile, err := os.Open(filename)
-	if err != nil {
-		fmt.Printf("Error opening file %s: %v\n", filename, err)
-		os.Exit(1)
-	}
-	defer worldFile.Close()
-
-	// Parse world header
-	var worldHeader HEADER
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.Version)
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.ObjectDataPos)
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.BlindObjectDataPos)
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.LightgridPos)
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.CollisionDataPos)
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.ParticleBlockerDataPos)
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.RenderDataPos)
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.PackerType)
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.PackerVersion)
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.Future[0])
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.Future[1])
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.Future[2])
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.Future[3])
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.Future[4])
-	_ = binary.Read(worldFile, binary.LittleEndian, &worldHeader.Future[5])
-	fmt.Println(worldHeader)
-
-	var world WORLD
-
-	// Parse world objects
-	_, err = worldFile.Seek(int64(worldHeader.ObjectDataPos), io.SeekStart)
-	fmt.Println("\nParsing WorldObjectsData @", worldHeader.ObjectDataPos)
-	if err != nil {
-		fmt.Printf("Error seeking to offset %d: %v\n", worldHeader.ObjectDataPos, err)
-		os.Exit(1)
-	}
-	_ = binary.Read(worldFile, binary.LittleEndian, &world.WorldObjectsLen)
-	fmt.Println("\nWorld Objects:", world.WorldObjectsLen)
-	for range world.WorldObjectsLen {
-		var worldObject WORLDOBJECT
-		_ = binary.Read(worldFile, binary.LittleEndian, &worldObject.ObjectSize)
-		readLithSTRING(worldFile, &worldObject.ObjectType)
-		_ = binary.Read(worldFile, binary.LittleEndian, &worldObject.PropertiesLen)
-		fmt.Println("\nProperty Count (", worldObject.ObjectType, "):", worldObject.PropertiesLen, "\n")
-		for range worldObject.PropertiesLen {
-			var property PROPERTY
-			readLithSTRING(worldFile, &property.Name)
-			_ = binary.Read(worldFile, binary.LittleEndian, &property.DataTypeFlag)
-			_ = binary.Read(worldFile, binary.LittleEndian, &property.PropertyFlags)
-			_ = binary.Read(worldFile, binary.LittleEndian, &property.DataSize)
-			switch property.DataTypeFlag {
-			case 0:
-				readLithSTRING(worldFile, &property.DataString)
-			case 1:
-				_ = binary.Read(worldFile, binary.LittleEndian, &property.DataVEC3)
-			case 2:
-				_ = binary.Read(worldFile, binary.LittleEndian, &property.DataCOLOR)
-			case 3:
-				_ = binary.Read(worldFile, binary.LittleEndian, &property.DataFloat)
-			case 5:
-				_ = binary.Read(worldFile, binary.LittleEndian, &property.DataBool)
-			case 6:
-				_ = binary.Read(worldFile, binary.LittleEndian, &property.DataUint32)
-			case 7:
-				_ = binary.Read(worldFile, binary.LittleEndian, &property.DataQuaternion)
-			}
-			fmt.Println(property)
-			worldObject.Properties = append(worldObject.Properties, property)
-		}
-		world.WorldObjects = append(world.WorldObjects, worldObject)
-	}
-
-	_, err = worldFile.Seek(int64(worldHeader.BlindObjectDataPos), io.SeekStart)
-	fmt.Println("\nParsing BlindObjectsData @", worldHeader.BlindObjectDataPos)
-	if err != nil {
-		fmt.Printf("Error seeking to offset %d: %v\n", worldHeader.BlindObjectDataPos, err)
-		os.Exit(1)
-	}
-	_ = binary.Read(worldFile, binary.LittleEndian, &world.BlindObjectDataSize)
-	fmt.Println(world.BlindObjectDataSize)
-
-	_, err = worldFile.Seek(int64(worldHeader.LightgridPos), io.SeekStart)
-	fmt.Println("\nParsing LightgridData @", worldHeader.LightgridPos)
-	if err != nil {
-		fmt.Printf("Error seeking to offset %d: %v\n", worldHeader.LightgridPos, err)
-		os.Exit(1)
-	}
-	// _ = binary.Read(worldFile, binary.LittleEndian, &)
-	// fmt.Println()
-
-	_, err = worldFile.Seek(int64(worldHeader.CollisionDataPos), io.SeekStart)
-	fmt.Println("\nParsing Physics/CollisionData @", worldHeader.CollisionDataPos)
-	if err != nil {
-		fmt.Printf("Error seeking to offset %d: %v\n", worldHeader.CollisionDataPos, err)
-		os.Exit(1)
-	}
-	_ = binary.Read(worldFile, binary.LittleEndian, &world.PhysicsDataLen)
-	fmt.Println(world.PhysicsDataLen)
-
-	_, err = worldFile.Seek(int64(worldHeader.ParticleBlockerDataPos), io.SeekStart)
-	fmt.Println("\nParsing ParticleBlockerData @", worldHeader.ParticleBlockerDataPos)
-	if err != nil {
-		fmt.Printf("Error seeking to offset %d: %v\n", worldHeader.ParticleBlockerDataPos, err)
-		os.Exit(1)
-	}
-	_ = binary.Read(worldFile, binary.LittleEndian, &world.ParticleBlockerDataLen)
-	fmt.Println(world.ParticleBlockerDataLen)
-
-	_, err = worldFile.Seek(int64(worldHeader.RenderDataPos), io.SeekStart)
-	fmt.Println("\nParsing RenderData @", worldHeader.ParticleBlockerDataPos)
-	if err != nil {
-		fmt.Printf("Error seeking to offset %d: %v\n", worldHeader.RenderDataPos, err)
-		os.Exit(1)
-	}
-
-	// fmt.Println(world)
-}
@@ -0,0 +1,166 @@
+package lithdat
+
+// This file adds collision queries on top of a parsed WORLDMODEL's BSP
+// (WMNODE tree + its polygon planes). A node classifies a point or
+// segment against the plane of the polygon it references
+// (Planes[Polies[node.PolyIndex].PlaneIndex]); NodeSidesIndicies[0] is
+// the front (plane distance >= 0) child, NodeSidesIndicies[1] the back
+// child. Either child may instead be the NODE_IN or NODE_OUT sentinel,
+// marking a solid or empty leaf rather than another interior node.
+
+// PointLeaf classifies p against wm's BSP, returning NODE_IN or
+// NODE_OUT, or, in principle, a leaf index should any other sentinel
+// scheme eventually replace the binary solid/empty one.
+func (wm *WORLDMODEL) PointLeaf(p VEC3) int32 {
+	return wm.classify(wm.RootNodeIndex, p)
+}
+
+func (wm *WORLDMODEL) classify(nodeIdx int32, p VEC3) int32 {
+	if nodeIdx == NODE_IN || nodeIdx == NODE_OUT {
+		return nodeIdx
+	}
+	if nodeIdx < 0 || int(nodeIdx) >= len(wm.Nodes) {
+		return NODE_OUT
+	}
+
+	node := wm.Nodes[nodeIdx]
+	plane, ok := wm.planeForNode(node)
+	if !ok {
+		return NODE_OUT
+	}
+	if plane.Normal.Dot(p)-plane.Dist >= 0 {
+		return wm.classify(node.NodeSidesIndicies[0], p)
+	}
+	return wm.classify(node.NodeSidesIndicies[1], p)
+}
+
+// LineTrace clips the segment a-b through wm's BSP and reports whether
+// it enters solid (NODE_IN) space. On a hit, frac is the fraction of
+// a-b (in [0,1]) at which the trace first touches solid, and plane is
+// the splitting plane it crossed to get there.
+func (wm *WORLDMODEL) LineTrace(a, b VEC3) (hit bool, frac float32, plane PLANE) {
+	return wm.lineTrace(wm.RootNodeIndex, a, b, 0, 1)
+}
+
+func (wm *WORLDMODEL) lineTrace(nodeIdx int32, p1, p2 VEC3, t1, t2 float32) (bool, float32, PLANE) {
+	if nodeIdx == NODE_OUT {
+		return false, 0, PLANE{}
+	}
+	if nodeIdx == NODE_IN {
+		return true, t1, PLANE{}
+	}
+	if nodeIdx < 0 || int(nodeIdx) >= len(wm.Nodes) {
+		return false, 0, PLANE{}
+	}
+
+	node := wm.Nodes[nodeIdx]
+	plane, ok := wm.planeForNode(node)
+	if !ok {
+		return false, 0, PLANE{}
+	}
+	d1 := plane.Normal.Dot(p1) - plane.Dist
+	d2 := plane.Normal.Dot(p2) - plane.Dist
+
+	switch {
+	case d1 >= 0 && d2 >= 0:
+		return wm.lineTrace(node.NodeSidesIndicies[0], p1, p2, t1, t2)
+	case d1 < 0 && d2 < 0:
+		return wm.lineTrace(node.NodeSidesIndicies[1], p1, p2, t1, t2)
+	}
+
+	// The segment straddles the plane: clip to the near half first, and
+	// only descend into the far half if the near half didn't hit.
+	splitFrac := d1 / (d1 - d2)
+	mid := p1.Lerp(p2, splitFrac)
+	tmid := t1 + (t2-t1)*splitFrac
+
+	near, far := node.NodeSidesIndicies[0], node.NodeSidesIndicies[1]
+	if d1 < 0 {
+		near, far = far, near
+	}
+
+	if hit, hitFrac, hitPlane := wm.lineTrace(near, p1, mid, t1, tmid); hit {
+		return true, hitFrac, hitPlane
+	}
+	if far == NODE_IN {
+		return true, tmid, plane
+	}
+	return wm.lineTrace(far, mid, p2, tmid, t2)
+}
+
+// BoxLeaves returns the sentinel leaves (NODE_IN/NODE_OUT) of every
+// part of wm's BSP whose region overlaps the axis-aligned box
+// min-max, descending into both children wherever the box straddles a
+// splitting plane.
+func (wm *WORLDMODEL) BoxLeaves(min, max VEC3) []int32 {
+	var leaves []int32
+	wm.boxLeaves(wm.RootNodeIndex, min, max, &leaves)
+	return leaves
+}
+
+func (wm *WORLDMODEL) boxLeaves(nodeIdx int32, min, max VEC3, out *[]int32) {
+	if nodeIdx == NODE_IN || nodeIdx == NODE_OUT {
+		*out = append(*out, nodeIdx)
+		return
+	}
+	if nodeIdx < 0 || int(nodeIdx) >= len(wm.Nodes) {
+		return
+	}
+
+	node := wm.Nodes[nodeIdx]
+	plane, ok := wm.planeForNode(node)
+	if !ok {
+		return
+	}
+	pos, neg := boxExtremeVertices(plane.Normal, min, max)
+	posDist := plane.Normal.Dot(pos) - plane.Dist
+	negDist := plane.Normal.Dot(neg) - plane.Dist
+
+	if negDist >= 0 {
+		wm.boxLeaves(node.NodeSidesIndicies[0], min, max, out)
+		return
+	}
+	if posDist < 0 {
+		wm.boxLeaves(node.NodeSidesIndicies[1], min, max, out)
+		return
+	}
+	wm.boxLeaves(node.NodeSidesIndicies[0], min, max, out)
+	wm.boxLeaves(node.NodeSidesIndicies[1], min, max, out)
+}
+
+// planeForNode resolves the plane node classifies against, reporting
+// false if PolyIndex or PlaneIndex is out of range for wm (a truncated
+// or hand-built WORLDMODEL) rather than panicking.
+func (wm *WORLDMODEL) planeForNode(node WMNODE) (PLANE, bool) {
+	if int(node.PolyIndex) >= len(wm.Polies) {
+		return PLANE{}, false
+	}
+	poly := wm.Polies[node.PolyIndex]
+	if int(poly.PlaneIndex) >= len(wm.Planes) {
+		return PLANE{}, false
+	}
+	return wm.Planes[poly.PlaneIndex], true
+}
+
+// boxExtremeVertices returns the two corners of the box min-max that
+// project furthest forward (pos) and furthest backward (neg) along
+// normal, the standard trick for classifying an AABB against a plane
+// without testing all eight corners.
+func boxExtremeVertices(normal, min, max VEC3) (pos, neg VEC3) {
+	if normal.X >= 0 {
+		pos.X, neg.X = max.X, min.X
+	} else {
+		pos.X, neg.X = min.X, max.X
+	}
+	if normal.Y >= 0 {
+		pos.Y, neg.Y = max.Y, min.Y
+	} else {
+		pos.Y, neg.Y = min.Y, max.Y
+	}
+	if normal.Z >= 0 {
+		pos.Z, neg.Z = max.Z, min.Z
+	} else {
+		pos.Z, neg.Z = min.Z, max.Z
+	}
+	return pos, neg
+}
@@ -0,0 +1,112 @@
+package lithdat
+
+import "testing"
+
+// planeSplitWorldModel is a one-node BSP splitting space at x=0: the
+// front half (x >= 0) is solid (NODE_IN), the back half (x < 0) is
+// empty (NODE_OUT).
+func planeSplitWorldModel() *WORLDMODEL {
+	return &WORLDMODEL{
+		Planes: []PLANE{{Normal: VEC3{X: 1, Y: 0, Z: 0}, Dist: 0}},
+		Polies: []WMPOLYGON{{PlaneIndex: 0}},
+		Nodes: []WMNODE{
+			{PolyIndex: 0, NodeSidesIndicies: [2]int32{NODE_IN, NODE_OUT}},
+		},
+		RootNodeIndex: 0,
+	}
+}
+
+// TestWorldModelOutOfRangePolyIndex exercises a WORLDMODEL whose single
+// node references a PolyIndex beyond Polies, as would be seen from a
+// truncated or hand-built tree. Queries should degrade to NODE_OUT/no
+// hit rather than panic.
+func TestWorldModelOutOfRangePolyIndex(t *testing.T) {
+	wm := &WORLDMODEL{
+		Nodes: []WMNODE{
+			{PolyIndex: 5, NodeSidesIndicies: [2]int32{NODE_IN, NODE_OUT}},
+		},
+		RootNodeIndex: 0,
+	}
+
+	if got := wm.PointLeaf(VEC3{X: 1}); got != NODE_OUT {
+		t.Errorf("PointLeaf with out-of-range PolyIndex = %d, want %d", got, NODE_OUT)
+	}
+	if hit, _, _ := wm.LineTrace(VEC3{X: -2}, VEC3{X: 2}); hit {
+		t.Errorf("LineTrace with out-of-range PolyIndex reported a hit, want none")
+	}
+	if got := wm.BoxLeaves(VEC3{X: -1, Y: -1, Z: -1}, VEC3{X: 1, Y: 1, Z: 1}); len(got) != 0 {
+		t.Errorf("BoxLeaves with out-of-range PolyIndex = %v, want none", got)
+	}
+}
+
+func TestWorldModelPointLeaf(t *testing.T) {
+	wm := planeSplitWorldModel()
+	tests := []struct {
+		name string
+		p    VEC3
+		want int32
+	}{
+		{"front", VEC3{X: 1}, NODE_IN},
+		{"back", VEC3{X: -1}, NODE_OUT},
+		{"on plane counts as front", VEC3{X: 0}, NODE_IN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wm.PointLeaf(tt.p); got != tt.want {
+				t.Errorf("PointLeaf(%v) = %d, want %d", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorldModelLineTrace(t *testing.T) {
+	wm := planeSplitWorldModel()
+	tests := []struct {
+		name     string
+		a, b     VEC3
+		wantHit  bool
+		wantFrac float32
+	}{
+		{"crosses into solid", VEC3{X: -2}, VEC3{X: 2}, true, 0.5},
+		{"starts solid", VEC3{X: 2}, VEC3{X: -2}, true, 0},
+		{"stays empty", VEC3{X: -2}, VEC3{X: -1}, false, 0},
+		{"stays solid", VEC3{X: 1}, VEC3{X: 2}, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, frac, _ := wm.LineTrace(tt.a, tt.b)
+			if hit != tt.wantHit {
+				t.Fatalf("LineTrace(%v, %v) hit = %v, want %v", tt.a, tt.b, hit, tt.wantHit)
+			}
+			if hit && frac != tt.wantFrac {
+				t.Errorf("LineTrace(%v, %v) frac = %v, want %v", tt.a, tt.b, frac, tt.wantFrac)
+			}
+		})
+	}
+}
+
+func TestWorldModelBoxLeaves(t *testing.T) {
+	wm := planeSplitWorldModel()
+	tests := []struct {
+		name     string
+		min, max VEC3
+		want     []int32
+	}{
+		{"entirely front", VEC3{X: 1, Y: -1, Z: -1}, VEC3{X: 2, Y: 1, Z: 1}, []int32{NODE_IN}},
+		{"entirely back", VEC3{X: -2, Y: -1, Z: -1}, VEC3{X: -1, Y: 1, Z: 1}, []int32{NODE_OUT}},
+		{"straddling", VEC3{X: -1, Y: -1, Z: -1}, VEC3{X: 1, Y: 1, Z: 1}, []int32{NODE_IN, NODE_OUT}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wm.BoxLeaves(tt.min, tt.max)
+			if len(got) != len(tt.want) {
+				t.Fatalf("BoxLeaves(%v, %v) = %v, want %v", tt.min, tt.max, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("BoxLeaves(%v, %v)[%d] = %d, want %d", tt.min, tt.max, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
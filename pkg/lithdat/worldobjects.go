@@ -0,0 +1,158 @@
+package lithdat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// WorldObjects returns the world's placed objects (lights, spawn
+// points, triggers, etc), decoding them from ObjectDataPos on first
+// call.
+func (f *File) WorldObjects() ([]WORLDOBJECT, error) {
+	f.worldObjectsOnce.Do(func() {
+		f.worldObjects, f.worldObjectsErr = f.readWorldObjects()
+	})
+	return f.worldObjects, f.worldObjectsErr
+}
+
+func (f *File) readWorldObjects() ([]WORLDOBJECT, error) {
+	r := f.sectionReader(f.Header.ObjectDataPos)
+
+	var count uint32
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, fmt.Errorf("lithdat: reading world object count: %w", err)
+	}
+
+	objects := make([]WORLDOBJECT, count)
+	for i := range objects {
+		obj, err := readWorldObject(r)
+		if err != nil {
+			return nil, fmt.Errorf("lithdat: reading world object %d: %w", i, err)
+		}
+		objects[i] = obj
+	}
+	return objects, nil
+}
+
+func readWorldObject(r io.Reader) (WORLDOBJECT, error) {
+	var obj WORLDOBJECT
+	if err := binary.Read(r, binary.LittleEndian, &obj.ObjectSize); err != nil {
+		return obj, err
+	}
+
+	var err error
+	if obj.ObjectType, err = readLithSTRING(r); err != nil {
+		return obj, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &obj.PropertiesLen); err != nil {
+		return obj, err
+	}
+
+	obj.Properties = make([]PROPERTY, obj.PropertiesLen)
+	for i := range obj.Properties {
+		prop, err := readProperty(r)
+		if err != nil {
+			return obj, fmt.Errorf("reading property %d: %w", i, err)
+		}
+		obj.Properties[i] = prop
+	}
+	return obj, nil
+}
+
+func readProperty(r io.Reader) (PROPERTY, error) {
+	var p PROPERTY
+	var err error
+	if p.Name, err = readLithSTRING(r); err != nil {
+		return p, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &p.DataTypeFlag); err != nil {
+		return p, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &p.PropertyFlags); err != nil {
+		return p, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &p.DataSize); err != nil {
+		return p, err
+	}
+
+	switch p.DataTypeFlag {
+	case 0:
+		p.DataString, err = readLithSTRING(r)
+	case 1:
+		err = binary.Read(r, binary.LittleEndian, &p.DataVEC3)
+	case 2:
+		err = binary.Read(r, binary.LittleEndian, &p.DataCOLOR)
+	case 3:
+		err = binary.Read(r, binary.LittleEndian, &p.DataFloat)
+	case 5:
+		err = binary.Read(r, binary.LittleEndian, &p.DataBool)
+	case 6:
+		err = binary.Read(r, binary.LittleEndian, &p.DataUint32)
+	case 7:
+		err = binary.Read(r, binary.LittleEndian, &p.DataQuaternion)
+	default:
+		err = fmt.Errorf("unknown property data type flag %d", p.DataTypeFlag)
+	}
+	if err != nil {
+		return p, fmt.Errorf("reading property %q data: %w", p.Name, err)
+	}
+	return p, nil
+}
+
+func writeWorldObject(w io.Writer, obj WORLDOBJECT) error {
+	if err := binary.Write(w, binary.LittleEndian, obj.ObjectSize); err != nil {
+		return err
+	}
+	if err := writeLithSTRING(w, obj.ObjectType); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(obj.Properties))); err != nil {
+		return err
+	}
+	for i, prop := range obj.Properties {
+		if err := writeProperty(w, prop); err != nil {
+			return fmt.Errorf("writing property %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func writeProperty(w io.Writer, p PROPERTY) error {
+	if err := writeLithSTRING(w, p.Name); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, p.DataTypeFlag); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, p.PropertyFlags); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, p.DataSize); err != nil {
+		return err
+	}
+
+	var err error
+	switch p.DataTypeFlag {
+	case 0:
+		err = writeLithSTRING(w, p.DataString)
+	case 1:
+		err = binary.Write(w, binary.LittleEndian, p.DataVEC3)
+	case 2:
+		err = binary.Write(w, binary.LittleEndian, p.DataCOLOR)
+	case 3:
+		err = binary.Write(w, binary.LittleEndian, p.DataFloat)
+	case 5:
+		err = binary.Write(w, binary.LittleEndian, p.DataBool)
+	case 6:
+		err = binary.Write(w, binary.LittleEndian, p.DataUint32)
+	case 7:
+		err = binary.Write(w, binary.LittleEndian, p.DataQuaternion)
+	default:
+		err = fmt.Errorf("unknown property data type flag %d", p.DataTypeFlag)
+	}
+	if err != nil {
+		return fmt.Errorf("writing property %q data: %w", p.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package lithdat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// WorldTree returns the world's BSP/octree layout and the physics world
+// models hung off it. Unlike the other sections, WorldTree isn't
+// reached through a HEADER offset: it (and the world info/extents
+// preamble ahead of it) sits immediately after the header, ending right
+// where ObjectDataPos picks up.
+func (f *File) WorldTree() (WORLDTREE, error) {
+	f.worldTreeOnce.Do(func() {
+		f.worldTree, f.worldTreeErr = f.readWorldTree()
+	})
+	return f.worldTree, f.worldTreeErr
+}
+
+func (f *File) readWorldTree() (WORLDTREE, error) {
+	r := f.sectionReader(headerSize)
+
+	var err error
+	if f.worldInfoStr, err = readLithSTRING(r); err != nil {
+		return WORLDTREE{}, fmt.Errorf("lithdat: reading world info string: %w", err)
+	}
+	for _, v := range []*VEC3{&f.worldExtentsMin, &f.worldExtentsMax, &f.worldOffset} {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			return WORLDTREE{}, fmt.Errorf("lithdat: reading world extents: %w", err)
+		}
+	}
+
+	return readWorldTree(r)
+}
+
+func readWorldTree(r io.Reader) (WORLDTREE, error) {
+	var t WORLDTREE
+	if err := binary.Read(r, binary.LittleEndian, &t.RootBBoxMin); err != nil {
+		return t, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &t.RootBBoxMax); err != nil {
+		return t, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &t.SubNodesLen); err != nil {
+		return t, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &t.TerrainDepth); err != nil {
+		return t, err
+	}
+
+	t.WorldLayout = make([]uint8, t.SubNodesLen)
+	if _, err := io.ReadFull(r, t.WorldLayout); err != nil {
+		return t, fmt.Errorf("reading world layout: %w", err)
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &t.WorldModelsLen); err != nil {
+		return t, err
+	}
+	t.WorldModels = make([]WORLDMODEL, t.WorldModelsLen)
+	for i := range t.WorldModels {
+		wm, err := readWorldModel(r)
+		if err != nil {
+			return t, fmt.Errorf("reading world model %d: %w", i, err)
+		}
+		t.WorldModels[i] = wm
+	}
+	return t, nil
+}
+
+func readWorldModel(r io.Reader) (WORLDMODEL, error) {
+	var wm WORLDMODEL
+	fields := []any{
+		&wm.Dummy, &wm.WorldInfoFlags,
+	}
+	for _, f := range fields {
+		if err := binary.Read(r, binary.LittleEndian, f); err != nil {
+			return wm, err
+		}
+	}
+
+	var err error
+	if wm.WorldName, err = readLithSTRING(r); err != nil {
+		return wm, err
+	}
+
+	lens := []any{
+		&wm.PointsLen, &wm.PlanesLen, &wm.SurfacesLen, &wm.Portals,
+		&wm.PoliesLen, &wm.LeavesLen, &wm.PoliesVerticesLen,
+		&wm.VisibleListLen, &wm.LeafList, &wm.NodesLen,
+	}
+	for _, l := range lens {
+		if err := binary.Read(r, binary.LittleEndian, l); err != nil {
+			return wm, err
+		}
+	}
+
+	bbox := []any{&wm.WorldBBoxMin, &wm.WorldBBoxMax, &wm.WorldTranslation}
+	for _, v := range bbox {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			return wm, err
+		}
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &wm.TextureNamesSize); err != nil {
+		return wm, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &wm.TextureNamesLen); err != nil {
+		return wm, err
+	}
+	wm.TextureNames = make([]string, wm.TextureNamesLen)
+	for i := range wm.TextureNames {
+		if wm.TextureNames[i], err = readLithSTRING(r); err != nil {
+			return wm, fmt.Errorf("reading texture name %d: %w", i, err)
+		}
+	}
+
+	wm.VerticesLen = make([]uint8, wm.PoliesLen)
+	if _, err := io.ReadFull(r, wm.VerticesLen); err != nil {
+		return wm, fmt.Errorf("reading poly vertex counts: %w", err)
+	}
+
+	wm.Planes = make([]PLANE, wm.PlanesLen)
+	for i := range wm.Planes {
+		if err := binary.Read(r, binary.LittleEndian, &wm.Planes[i]); err != nil {
+			return wm, fmt.Errorf("reading plane %d: %w", i, err)
+		}
+	}
+
+	wm.Surfaces = make([]SURFACE, wm.SurfacesLen)
+	for i := range wm.Surfaces {
+		if err := binary.Read(r, binary.LittleEndian, &wm.Surfaces[i]); err != nil {
+			return wm, fmt.Errorf("reading surface %d: %w", i, err)
+		}
+	}
+
+	wm.Polies = make([]WMPOLYGON, wm.PoliesLen)
+	for i := range wm.Polies {
+		var p WMPOLYGON
+		if err := binary.Read(r, binary.LittleEndian, &p.SurfaceIndex); err != nil {
+			return wm, err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &p.PlaneIndex); err != nil {
+			return wm, err
+		}
+		p.VerticesIndicies = make([]uint32, wm.VerticesLen[i])
+		for j := range p.VerticesIndicies {
+			if err := binary.Read(r, binary.LittleEndian, &p.VerticesIndicies[j]); err != nil {
+				return wm, fmt.Errorf("reading poly %d vertex index %d: %w", i, j, err)
+			}
+		}
+		wm.Polies[i] = p
+	}
+
+	wm.Nodes = make([]WMNODE, wm.NodesLen)
+	for i := range wm.Nodes {
+		if err := binary.Read(r, binary.LittleEndian, &wm.Nodes[i]); err != nil {
+			return wm, fmt.Errorf("reading node %d: %w", i, err)
+		}
+	}
+
+	wm.Points = make([]VEC3, wm.PointsLen)
+	for i := range wm.Points {
+		if err := binary.Read(r, binary.LittleEndian, &wm.Points[i]); err != nil {
+			return wm, fmt.Errorf("reading point %d: %w", i, err)
+		}
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &wm.RootNodeIndex); err != nil {
+		return wm, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &wm.Sections); err != nil {
+		return wm, err
+	}
+	return wm, nil
+}
+
+func writeWorldTree(w io.Writer, t *WORLDTREE) error {
+	if err := binary.Write(w, binary.LittleEndian, t.RootBBoxMin); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, t.RootBBoxMax); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(t.WorldLayout))); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, t.TerrainDepth); err != nil {
+		return err
+	}
+	if _, err := w.Write(t.WorldLayout); err != nil {
+		return fmt.Errorf("writing world layout: %w", err)
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(t.WorldModels))); err != nil {
+		return err
+	}
+	for i, wm := range t.WorldModels {
+		if err := writeWorldModel(w, wm); err != nil {
+			return fmt.Errorf("writing world model %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func writeWorldModel(w io.Writer, wm WORLDMODEL) error {
+	if err := binary.Write(w, binary.LittleEndian, wm.Dummy); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, wm.WorldInfoFlags); err != nil {
+		return err
+	}
+	if err := writeLithSTRING(w, wm.WorldName); err != nil {
+		return err
+	}
+
+	lens := []uint32{
+		uint32(len(wm.Points)), uint32(len(wm.Planes)), uint32(len(wm.Surfaces)), wm.Portals,
+		uint32(len(wm.Polies)), wm.LeavesLen, wm.PoliesVerticesLen,
+		wm.VisibleListLen, wm.LeafList, uint32(len(wm.Nodes)),
+	}
+	for _, l := range lens {
+		if err := binary.Write(w, binary.LittleEndian, l); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range []VEC3{wm.WorldBBoxMin, wm.WorldBBoxMax, wm.WorldTranslation} {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, wm.TextureNamesSize); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(wm.TextureNames))); err != nil {
+		return err
+	}
+	for i, name := range wm.TextureNames {
+		if err := writeLithSTRING(w, name); err != nil {
+			return fmt.Errorf("writing texture name %d: %w", i, err)
+		}
+	}
+
+	if _, err := w.Write(wm.VerticesLen); err != nil {
+		return fmt.Errorf("writing poly vertex counts: %w", err)
+	}
+
+	for i, p := range wm.Planes {
+		if err := binary.Write(w, binary.LittleEndian, p); err != nil {
+			return fmt.Errorf("writing plane %d: %w", i, err)
+		}
+	}
+
+	for i, s := range wm.Surfaces {
+		if err := binary.Write(w, binary.LittleEndian, s); err != nil {
+			return fmt.Errorf("writing surface %d: %w", i, err)
+		}
+	}
+
+	for i, p := range wm.Polies {
+		if err := binary.Write(w, binary.LittleEndian, p.SurfaceIndex); err != nil {
+			return err
+		}
+		if err := binary.Write(w, binary.LittleEndian, p.PlaneIndex); err != nil {
+			return err
+		}
+		for j, idx := range p.VerticesIndicies {
+			if err := binary.Write(w, binary.LittleEndian, idx); err != nil {
+				return fmt.Errorf("writing poly %d vertex index %d: %w", i, j, err)
+			}
+		}
+	}
+
+	for i, n := range wm.Nodes {
+		if err := binary.Write(w, binary.LittleEndian, n); err != nil {
+			return fmt.Errorf("writing node %d: %w", i, err)
+		}
+	}
+
+	for i, p := range wm.Points {
+		if err := binary.Write(w, binary.LittleEndian, p); err != nil {
+			return fmt.Errorf("writing point %d: %w", i, err)
+		}
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, wm.RootNodeIndex); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, wm.Sections); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package lithdat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// WriteTo serializes w back into a valid Lithtech world file. It writes
+// a placeholder header first, then each section in the order File
+// reads them, recording where every section landed; once everything
+// has been written it back-patches the header's offsets and flushes
+// the whole thing to out.
+func (w *WORLD) WriteTo(out io.Writer) (int64, error) {
+	var buf bytes.Buffer
+
+	header := w.Header
+	if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+		return 0, fmt.Errorf("lithdat: reserving header: %w", err)
+	}
+
+	if err := writeLithSTRING(&buf, w.WorldInfoStr); err != nil {
+		return 0, fmt.Errorf("lithdat: writing world info string: %w", err)
+	}
+	for _, v := range []VEC3{w.WorldExtentsMin, w.WorldExtentsMax, w.WorldOffset} {
+		if err := binary.Write(&buf, binary.LittleEndian, v); err != nil {
+			return 0, fmt.Errorf("lithdat: writing world extents: %w", err)
+		}
+	}
+	if err := writeWorldTree(&buf, &w.WorldTree); err != nil {
+		return 0, fmt.Errorf("lithdat: writing world tree: %w", err)
+	}
+
+	header.ObjectDataPos = uint32(buf.Len())
+	if err := binary.Write(&buf, binary.LittleEndian, uint32(len(w.WorldObjects))); err != nil {
+		return 0, err
+	}
+	for i, obj := range w.WorldObjects {
+		if err := writeWorldObject(&buf, obj); err != nil {
+			return 0, fmt.Errorf("lithdat: writing world object %d: %w", i, err)
+		}
+	}
+
+	header.BlindObjectDataPos = uint32(buf.Len())
+	if err := binary.Write(&buf, binary.LittleEndian, uint32(len(w.BlindObjectsData))); err != nil {
+		return 0, err
+	}
+	for i, o := range w.BlindObjectsData {
+		if err := writeBlindObjectData(&buf, o); err != nil {
+			return 0, fmt.Errorf("lithdat: writing blind object %d: %w", i, err)
+		}
+	}
+
+	header.LightgridPos = uint32(buf.Len())
+	if err := writeLightGrid(&buf, &w.LightGrid); err != nil {
+		return 0, fmt.Errorf("lithdat: writing lightgrid: %w", err)
+	}
+
+	header.CollisionDataPos = uint32(buf.Len())
+	if err := binary.Write(&buf, binary.LittleEndian, uint32(len(w.Polies))); err != nil {
+		return 0, err
+	}
+	for i, p := range w.Polies {
+		if err := writePolygon(&buf, p); err != nil {
+			return 0, fmt.Errorf("lithdat: writing collision polygon %d: %w", i, err)
+		}
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, w.Zero); err != nil {
+		return 0, err
+	}
+
+	header.ParticleBlockerDataPos = uint32(buf.Len())
+	if err := binary.Write(&buf, binary.LittleEndian, uint32(len(w.ParticleBlockers))); err != nil {
+		return 0, err
+	}
+	for i, p := range w.ParticleBlockers {
+		if err := writePolygon(&buf, p); err != nil {
+			return 0, fmt.Errorf("lithdat: writing particle blocker %d: %w", i, err)
+		}
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, w.Zero2); err != nil {
+		return 0, err
+	}
+
+	header.RenderDataPos = uint32(buf.Len())
+	if err := writeRenderData(&buf, &w.RenderData); err != nil {
+		return 0, fmt.Errorf("lithdat: writing render data: %w", err)
+	}
+
+	out2 := buf.Bytes()
+	var headerBuf bytes.Buffer
+	if err := binary.Write(&headerBuf, binary.LittleEndian, header); err != nil {
+		return 0, fmt.Errorf("lithdat: encoding header: %w", err)
+	}
+	copy(out2[:headerSize], headerBuf.Bytes())
+
+	n, err := out.Write(out2)
+	return int64(n), err
+}
+
+// writeLithSTRING writes s as a Lithtech length-prefixed string: a
+// little endian uint16 byte count followed by the raw bytes.
+func writeLithSTRING(w io.Writer, s string) error {
+	if len(s) > math.MaxUint16 {
+		return fmt.Errorf("lithdat: string %q longer than %d bytes", s, math.MaxUint16)
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}